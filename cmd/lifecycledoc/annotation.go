@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/multierror"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+)
+
+// annotateDecodeError writes a GitHub workflow error command for the decode error of the lifecycle file "path",
+// allowing GitHub to show the error inline in the file. Does nothing outside GitHub Actions
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#setting-an-error-message
+func annotateDecodeError(path string, err error) {
+	if os.Getenv("GITHUB_ACTIONS") != "true" {
+		return
+	}
+
+	// One annotation per invalid definition
+	for _, err := range multierror.Flatten(err) {
+		annotateError(path, err)
+	}
+}
+
+// annotateResolutionErrors writes a GitHub workflow error command for each resolution error of the lifecycle file "path",
+// positioned in the definition that can't be resolved. Does nothing outside GitHub Actions
+func annotateResolutionErrors(path string, errs []error) {
+	if os.Getenv("GITHUB_ACTIONS") != "true" {
+		return
+	}
+
+	for i := range errs {
+		annotateError(path, errs[i])
+	}
+}
+
+func annotateError(path string, err error) {
+	var (
+		file       = path
+		properties []string
+		message    = err.Error()
+		decodeErr  *yaml.DecodeError
+	)
+
+	if errors.As(err, &decodeErr) {
+		// The errors of external references are in the referenced files
+		if len(decodeErr.File) > 0 {
+			file = decodeErr.File
+		}
+
+		if decodeErr.Line > 0 {
+			properties = append(properties, fmt.Sprintf("line=%d", decodeErr.Line))
+
+			if decodeErr.Column > 0 {
+				properties = append(properties, fmt.Sprintf("col=%d", decodeErr.Column))
+			}
+
+			// The position is already in the annotation
+			message = decodeErr.Err.Error()
+			if len(decodeErr.Path) > 0 {
+				message = fmt.Sprintf("%s: %s", decodeErr.Path, message)
+			}
+		}
+	} else if definitionErr := innermostDefinitionError(err); definitionErr != nil {
+		// The definitions of external documents are in the referenced files
+		if len(definitionErr.File) > 0 {
+			file = definitionErr.File
+		}
+
+		if definitionErr.Line > 0 {
+			properties = append(properties, fmt.Sprintf("line=%d", definitionErr.Line))
+
+			if definitionErr.Column > 0 {
+				properties = append(properties, fmt.Sprintf("col=%d", definitionErr.Column))
+			}
+		}
+	}
+
+	properties = append([]string{"file=" + escapeAnnotationProperty(file)}, properties...)
+
+	// The workflow commands are also read from stderr, keeping stdout for the command output
+	fmt.Fprintf(os.Stderr, "::error %s::%s\n", strings.Join(properties, ","), escapeAnnotationData(message))
+}
+
+// innermostDefinitionError returns the deepest definition error in the chain of "err", which is the definition that
+// causes the error when a reference fails because of the referenced definition. Returns nil without definition errors
+func innermostDefinitionError(err error) *schema.DefinitionError {
+	var innermost *schema.DefinitionError
+
+	for {
+		var definitionErr *schema.DefinitionError
+		if !errors.As(err, &definitionErr) {
+			return innermost
+		}
+
+		innermost = definitionErr
+		err = definitionErr.Err
+	}
+}
+
+func escapeAnnotationData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeAnnotationProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/multierror"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+)
+
+func TestShouldEscapeAnnotations(t *testing.T) {
+	testCases := []struct {
+		input            string
+		expectedData     string
+		expectedProperty string
+	}{
+		{input: "100%", expectedData: "100%25", expectedProperty: "100%25"},
+		{input: "line\r\nbreak", expectedData: "line%0D%0Abreak", expectedProperty: "line%0D%0Abreak"},
+		{input: "C:/lifecycle.yaml", expectedData: "C:/lifecycle.yaml", expectedProperty: "C%3A/lifecycle.yaml"},
+		{input: "bolos,tortas.yaml", expectedData: "bolos,tortas.yaml", expectedProperty: "bolos%2Ctortas.yaml"},
+		// The escaped sequences must not be escaped again
+		{input: "%0A:\n", expectedData: "%250A:%0A", expectedProperty: "%250A%3A%0A"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("should escape %q", testCase.input), func(t *testing.T) {
+			if data := escapeAnnotationData(testCase.input); data != testCase.expectedData {
+				t.Errorf("expected data '%s', received '%s'", testCase.expectedData, data)
+			}
+
+			if property := escapeAnnotationProperty(testCase.input); property != testCase.expectedProperty {
+				t.Errorf("expected property '%s', received '%s'", testCase.expectedProperty, property)
+			}
+		})
+	}
+}
+
+func TestShouldAnnotateErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		githubActions string
+		annotate      func(path string, err error)
+		err           error
+		expected      string
+	}{
+		{
+			name:          "should not annotate outside GitHub Actions",
+			githubActions: "",
+			err:           errors.New("invalid definition"),
+			expected:      "",
+		},
+		{
+			name:          "should annotate errors without position in the file",
+			githubActions: "true",
+			err:           errors.New("invalid definition"),
+			expected:      "::error file=docs/lifecycle%2C events.yaml::invalid definition\n",
+		},
+		{
+			name:          "should annotate each decode error in its position",
+			githubActions: "true",
+			err: multierror.Append(
+				&yaml.DecodeError{Line: 3, Column: 5, Path: "#/types/Cake/type", Err: errors.New("invalid type 'cake'")},
+				&yaml.DecodeError{File: "common.yaml", Line: 7, Column: 1, Err: errors.New("multi\nline")},
+			),
+			expected: "::error file=docs/lifecycle%2C events.yaml,line=3,col=5::#/types/Cake/type: invalid type 'cake'\n" +
+				"::error file=common.yaml,line=7,col=1::multi%0Aline\n",
+		},
+		{
+			name:          "should annotate resolution errors in the innermost definition",
+			githubActions: "true",
+			annotate: func(path string, err error) {
+				annotateResolutionErrors(path, []error{err})
+			},
+			err: fmt.Errorf("lifecycle: %w", &schema.DefinitionError{
+				Line:   9,
+				Column: 15,
+				Path:   "#/events/published/CAKE_BURNED/attributes",
+				Err: fmt.Errorf("can't resolve reference: %w", &schema.DefinitionError{
+					File:   "common.yaml",
+					Line:   5,
+					Column: 11,
+					Path:   "#/types/Money",
+					Err:    errors.New("definition '#/types/Mony' not found"),
+				}),
+			}),
+			expected: "::error file=common.yaml,line=5,col=11::lifecycle: can't resolve reference: definition '#/types/Mony' not found\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Setenv("GITHUB_ACTIONS", testCase.githubActions)
+
+			annotate := testCase.annotate
+			if annotate == nil {
+				annotate = annotateDecodeError
+			}
+
+			output := captureOutput(t, &os.Stderr, func() {
+				annotate("docs/lifecycle, events.yaml", testCase.err)
+			})
+
+			if output != testCase.expected {
+				t.Errorf("expected '%s', received '%s'", testCase.expected, output)
+			}
+		})
+	}
+}
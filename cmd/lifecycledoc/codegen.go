@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/codegen/golang"
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/codegen/php"
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/codegen/python"
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/codegen/typescript"
+	"github.com/spf13/cobra"
+)
+
+const (
+	zodFlag       = "zod"
+	packageFlag   = "package"
+	namespaceFlag = "namespace"
+)
+
+func newCodegenCommand() *cobra.Command {
+	codegenCmd := &cobra.Command{
+		Use:   "codegen",
+		Short: "Generate the code of the types and published events of the lifecycle.yaml file definition",
+	}
+
+	codegenCmd.AddCommand(newCodegenTypeScriptCommand())
+	codegenCmd.AddCommand(newCodegenGoCommand())
+	codegenCmd.AddCommand(newCodegenPHPCommand())
+	codegenCmd.AddCommand(newCodegenPythonCommand())
+
+	return codegenCmd
+}
+
+func newCodegenTypeScriptCommand() *cobra.Command {
+	typeScriptCmd := &cobra.Command{
+		Use:   "typescript [lifecycle.yaml file path]",
+		Short: "Generate the TypeScript types of the lifecycle.yaml file definition",
+		Long: `Generate the TypeScript types of the lifecycle.yaml file definition.
+
+The objects are generated as interfaces, the arrays as T[], the enums as unions of literals and the
+nullable definitions as T | null. Each published event is generated as an interface with its attributes
+and entities, named by the event with the Event suffix, like CakeBurnedEvent.`,
+		Args: cobra.ExactArgs(1),
+		RunE: generateTypeScript,
+	}
+
+	typeScriptCmd.Flags().String(outFlag, "", "Specifies the output file. Writes to the standard output when not specified")
+	typeScriptCmd.Flags().Bool(zodFlag, false, "Generates the zod schemas of the types, named by the type with the Schema suffix")
+	addSchemaPathFlag(typeScriptCmd)
+	addInputFormatFlag(typeScriptCmd)
+
+	return typeScriptCmd
+}
+
+func generateTypeScript(cmd *cobra.Command, args []string) error {
+	schemaResolver, err := decodeLifecycleFile(args[0], newDecodeOptions(cmd))
+	if err != nil {
+		annotateDecodeError(args[0], err)
+		return err
+	}
+
+	var (
+		out, _ = cmd.Flags().GetString(outFlag)
+		zod, _ = cmd.Flags().GetBool(zodFlag)
+	)
+
+	return writeOutput(out, func(w io.Writer) error {
+		if err := typescript.NewWriter(zod).Write(w, schemaResolver); err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+
+		return nil
+	})
+}
+
+func newCodegenGoCommand() *cobra.Command {
+	goCmd := &cobra.Command{
+		Use:   "go [lifecycle.yaml file path]",
+		Short: "Generate the Go types of the lifecycle.yaml file definition",
+		Long: `Generate the Go types of the lifecycle.yaml file definition.
+
+The objects are generated as structs with JSON tags, the enums of strings as string types with a constant
+by value and the nullable definitions as pointers. Each published event is generated as a struct with its
+attributes and entities, named by the event with the Event suffix, like CakeBurnedEvent.`,
+		Args: cobra.ExactArgs(1),
+		RunE: generateGo,
+	}
+
+	goCmd.Flags().String(outFlag, "", "Specifies the output file. Writes to the standard output when not specified")
+	goCmd.Flags().String(packageFlag, "events", "Specifies the package name of the generated code")
+	addSchemaPathFlag(goCmd)
+	addInputFormatFlag(goCmd)
+
+	return goCmd
+}
+
+func generateGo(cmd *cobra.Command, args []string) error {
+	schemaResolver, err := decodeLifecycleFile(args[0], newDecodeOptions(cmd))
+	if err != nil {
+		annotateDecodeError(args[0], err)
+		return err
+	}
+
+	var (
+		out, _         = cmd.Flags().GetString(outFlag)
+		packageName, _ = cmd.Flags().GetString(packageFlag)
+	)
+
+	return writeOutput(out, func(w io.Writer) error {
+		if err := golang.NewWriter(packageName).Write(w, schemaResolver); err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+
+		return nil
+	})
+}
+
+func newCodegenPHPCommand() *cobra.Command {
+	phpCmd := &cobra.Command{
+		Use:   "php [lifecycle.yaml file path]",
+		Short: "Generate the PHP 8.1 classes and enums of the lifecycle.yaml file definition",
+		Long: `Generate the PHP 8.1 classes and enums of the lifecycle.yaml file definition.
+
+The objects are generated as final classes with readonly properties, the enums of strings and integers
+as backed enums and the arrays as PHP arrays typed by docblocks. One file is written per class or enum,
+following the PSR-4 layout of the namespace: the declared types in the Types namespace and the published
+events in the namespace of their module.`,
+		Args: cobra.ExactArgs(1),
+		RunE: generatePHP,
+	}
+
+	phpCmd.Flags().String(outFlag, ".", "Specifies the output directory, which is the PSR-4 base directory of the namespace")
+	phpCmd.Flags().String(namespaceFlag, "App\\Events", "Specifies the root namespace of the generated code")
+	addSchemaPathFlag(phpCmd)
+	addInputFormatFlag(phpCmd)
+
+	return phpCmd
+}
+
+func generatePHP(cmd *cobra.Command, args []string) error {
+	schemaResolver, err := decodeLifecycleFile(args[0], newDecodeOptions(cmd))
+	if err != nil {
+		annotateDecodeError(args[0], err)
+		return err
+	}
+
+	var (
+		outDir, _    = cmd.Flags().GetString(outFlag)
+		namespace, _ = cmd.Flags().GetString(namespaceFlag)
+	)
+
+	paths, err := php.NewFileGenerator(outDir, namespace).Generate(schemaResolver)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	logger := log.New(os.Stderr, "", log.Lmicroseconds)
+	for i := range paths {
+		logger.Printf("file written: %s", paths[i])
+	}
+
+	return nil
+}
+
+func newCodegenPythonCommand() *cobra.Command {
+	pythonCmd := &cobra.Command{
+		Use:   "python [lifecycle.yaml file path]",
+		Short: "Generate the pydantic models of the lifecycle.yaml file definition",
+		Long: `Generate the pydantic models of the lifecycle.yaml file definition.
+
+The objects are generated as models with the fields in snake_case, aliased by the property names, the enums
+as literals and the nullable definitions as optionals. The date-time, date, time and uuid formats are generated
+as their Python types. Each published event is generated as a model with its attributes and entities, named by
+the event with the Event suffix, like CakeBurnedEvent.`,
+		Args: cobra.ExactArgs(1),
+		RunE: generatePython,
+	}
+
+	pythonCmd.Flags().String(outFlag, "", "Specifies the output file. Writes to the standard output when not specified")
+	addSchemaPathFlag(pythonCmd)
+	addInputFormatFlag(pythonCmd)
+
+	return pythonCmd
+}
+
+func generatePython(cmd *cobra.Command, args []string) error {
+	schemaResolver, err := decodeLifecycleFile(args[0], newDecodeOptions(cmd))
+	if err != nil {
+		annotateDecodeError(args[0], err)
+		return err
+	}
+
+	out, _ := cmd.Flags().GetString(outFlag)
+
+	return writeOutput(out, func(w io.Writer) error {
+		if err := python.NewWriter().Write(w, schemaResolver); err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/compat"
+	"github.com/spf13/cobra"
+)
+
+const (
+	modeFlag = "mode"
+)
+
+func newCompatCommand() *cobra.Command {
+	compatCmd := &cobra.Command{
+		Use:   "compat [old lifecycle.yaml file path] [new lifecycle.yaml file path]",
+		Short: "Detect breaking changes in the published events between two lifecycle.yaml files",
+		Long: `Detect breaking changes in the published events between two lifecycle.yaml files.
+
+When the baseRef flag is specified only the new lifecycle.yaml file path is required,
+the old file is loaded from the same path at the git ref.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: checkCompatibility,
+	}
+
+	compatCmd.Flags().String(modeFlag, "full", "Specifies the compatibility mode. Supported modes: backward, forward, full")
+	compatCmd.Flags().String(baseRefFlag, "", "Loads the old lifecycle file from the new file path at the git ref (e.g. origin/main)")
+	addSchemaPathFlag(compatCmd)
+	addInputFormatFlag(compatCmd)
+
+	return compatCmd
+}
+
+func checkCompatibility(cmd *cobra.Command, args []string) error {
+	modeName, _ := cmd.Flags().GetString(modeFlag)
+
+	mode, err := compat.NewMode(modeName)
+	if err != nil {
+		return err
+	}
+
+	var oldSchemaResolver *schema.BasicResolver
+
+	switch baseRef, _ := cmd.Flags().GetString(baseRefFlag); {
+	case len(baseRef) > 0 && len(args) == 1:
+		oldSchemaResolver, err = decodeLifecycleFileAtRef(args[0], baseRef, newDecodeOptions(cmd))
+	case len(baseRef) < 1 && len(args) == 2:
+		oldSchemaResolver, err = decodeLifecycleFile(args[0], newDecodeOptions(cmd))
+	default:
+		return fmt.Errorf("specify the old and new lifecycle file paths or only the new one with the '%s' flag", baseRefFlag)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	newSchemaResolver, err := decodeLifecycleFile(args[len(args)-1], newDecodeOptions(cmd))
+	if err != nil {
+		return err
+	}
+
+	incompatibilities, err := compat.NewChecker(mode).Check(oldSchemaResolver, newSchemaResolver)
+	if err != nil {
+		return err
+	}
+
+	if len(incompatibilities) > 0 {
+		errs := make([]error, len(incompatibilities))
+		for i := range incompatibilities {
+			errs[i] = errors.New(incompatibilities[i].String())
+		}
+
+		return newErrorList(fmt.Sprintf("the following changes break the %s compatibility", mode), errs)
+	}
+
+	log.New(os.Stdout, "", log.Lmicroseconds).Printf("no changes break the %s compatibility", mode)
+	return nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/confluence"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/textdiff"
+	"github.com/spf13/cobra"
+)
+
+const (
+	diffExitCodeChanges = 1
+	diffExitCodeFailure = 2
+)
+
+func newDiffCommand() *cobra.Command {
+	diffCmd := &cobra.Command{
+		Use:   "diff [lifecycle.yaml file path]",
+		Short: "Show the changes that would be published to the Confluence pages",
+		Long: `Show the changes that would be published to the Confluence pages in unified diff format.
+
+When the baseRef flag is specified the rendered content is compared with the content
+rendered from the same lifecycle.yaml file at the git ref, without contacting Confluence.
+
+Exit codes:
+  0 - the pages are up to date
+  1 - the pages have changes
+  2 - an error occurred`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return &exitCodeError{code: diffExitCodeFailure, err: err}
+			}
+
+			return nil
+		},
+		RunE: diff,
+		// The errors are reported by the main function using the command exit code
+		SilenceErrors: true,
+	}
+
+	// The usage errors must not be confused with the exit code of the changes
+	diffCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &exitCodeError{code: diffExitCodeFailure, err: err}
+	})
+
+	diffCmd.Flags().String(titlePrefixFlag, "", "Specifies a prefix for Confluence page titles")
+	diffCmd.Flags().String(baseRefFlag, "", "Compares with the lifecycle file at the git ref (e.g. origin/main) instead of Confluence")
+	addSchemaPathFlag(diffCmd)
+	addInputFormatFlag(diffCmd)
+
+	return diffCmd
+}
+
+func diff(cmd *cobra.Command, args []string) error {
+	options := newDecodeOptions(cmd)
+
+	schemaResolver, err := decodeLifecycleFile(args[0], options)
+	if err != nil {
+		return &exitCodeError{code: diffExitCodeFailure, err: err}
+	}
+
+	if baseRef, _ := cmd.Flags().GetString(baseRefFlag); len(baseRef) > 0 {
+		return diffWithBaseRef(args[0], baseRef, options, schemaResolver)
+	}
+
+	generator, err := newConfluenceGenerator()
+	if err != nil {
+		return &exitCodeError{code: diffExitCodeFailure, err: err}
+	}
+
+	resultChan, err := generator.Diff(context.Background(), schemaResolver)
+	if err != nil {
+		return &exitCodeError{code: diffExitCodeFailure, err: err}
+	}
+
+	var (
+		errs       []error
+		hasChanges bool
+	)
+
+	for result := range resultChan {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+			continue
+		}
+
+		if len(result.Diff) > 0 {
+			hasChanges = true
+			fmt.Fprint(os.Stdout, result.Diff)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &exitCodeError{
+			code: diffExitCodeFailure,
+			err:  newErrorList("the following errors occur when comparing documentation pages", errs),
+		}
+	}
+
+	if hasChanges {
+		return &exitCodeError{code: diffExitCodeChanges}
+	}
+
+	return nil
+}
+
+func diffWithBaseRef(path, baseRef string, options decodeOptions, schemaResolver schema.Resolver) error {
+	baseSchemaResolver, err := decodeLifecycleFileAtRef(path, baseRef, options)
+	if err != nil {
+		return &exitCodeError{code: diffExitCodeFailure, err: err}
+	}
+
+	baseContent, err := renderStorage(baseSchemaResolver)
+	if err != nil {
+		return &exitCodeError{code: diffExitCodeFailure, err: fmt.Errorf("can't render '%s' at git ref '%s': %w", path, baseRef, err)}
+	}
+
+	content, err := renderStorage(schemaResolver)
+	if err != nil {
+		return &exitCodeError{code: diffExitCodeFailure, err: fmt.Errorf("can't render '%s': %w", path, err)}
+	}
+
+	result := textdiff.Unified(
+		fmt.Sprintf("%s@%s", path, baseRef),
+		path,
+		strings.Split(baseContent, "\n"),
+		strings.Split(content, "\n"),
+	)
+
+	if len(result) > 0 {
+		fmt.Fprint(os.Stdout, result)
+		return &exitCodeError{code: diffExitCodeChanges}
+	}
+
+	return nil
+}
+
+func renderStorage(schemaResolver schema.Resolver) (string, error) {
+	out := &strings.Builder{}
+	if err := confluence.NewTemplateWriter(confluence.NewHTMLTemplateRetriver()).Write(out, schemaResolver); err != nil {
+		return "", err
+	}
+
+	return out.String(), nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/asyncapi"
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/avro"
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/jsonschema"
+	"github.com/spf13/cobra"
+)
+
+const (
+	outFlag             = "out"
+	documentVersionFlag = "documentVersion"
+)
+
+func newExportCommand() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the lifecycle.yaml file definition to other specification formats",
+	}
+
+	exportCmd.AddCommand(newExportAsyncAPICommand())
+	exportCmd.AddCommand(newExportJSONSchemaCommand())
+	exportCmd.AddCommand(newExportAvroCommand())
+
+	return exportCmd
+}
+
+func newExportAsyncAPICommand() *cobra.Command {
+	asyncAPICmd := &cobra.Command{
+		Use:   "asyncapi [lifecycle.yaml file path]",
+		Short: "Export the lifecycle.yaml file definition as an AsyncAPI 3.0 document",
+		Long: `Export the lifecycle.yaml file definition as an AsyncAPI 3.0 document.
+
+The published events are exported as send operations and the consumed events as receive operations.
+The types are exported as JSON Schema components and the visibility and module of the published
+events are kept in the x-visibility and x-module extensions of the messages.`,
+		Args: cobra.ExactArgs(1),
+		RunE: exportAsyncAPI,
+	}
+
+	asyncAPICmd.Flags().String(outFlag, "", "Specifies the output file. The document is written as JSON for .json files, otherwise as YAML. Writes to the standard output when not specified")
+	asyncAPICmd.Flags().String(documentVersionFlag, "1.0.0", "Specifies the version of the application API, written in the info.version of the document")
+	addSchemaPathFlag(asyncAPICmd)
+	addInputFormatFlag(asyncAPICmd)
+
+	return asyncAPICmd
+}
+
+func exportAsyncAPI(cmd *cobra.Command, args []string) error {
+	schemaResolver, err := decodeLifecycleFile(args[0], newDecodeOptions(cmd))
+	if err != nil {
+		annotateDecodeError(args[0], err)
+		return err
+	}
+
+	var (
+		out, _     = cmd.Flags().GetString(outFlag)
+		version, _ = cmd.Flags().GetString(documentVersionFlag)
+		format     = asyncapi.FormatYAML
+	)
+
+	if strings.EqualFold(filepath.Ext(out), ".json") {
+		format = asyncapi.FormatJSON
+	}
+
+	return writeOutput(out, func(w io.Writer) error {
+		if err := asyncapi.NewDocumentWriter(version, format).Write(w, schemaResolver); err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+
+		return nil
+	})
+}
+
+func newExportJSONSchemaCommand() *cobra.Command {
+	jsonSchemaCmd := &cobra.Command{
+		Use:   "jsonschema [lifecycle.yaml file path]",
+		Short: "Export a JSON Schema document for each published event of the lifecycle.yaml file definition",
+		Long: `Export a JSON Schema (Draft 2020-12) document for each published event of the lifecycle.yaml file definition.
+
+Each document describes the message with the attributes and entities of the event and declares the
+types in its $defs, allowing consumers to validate the messages with JSON Schema tools.`,
+		Args: cobra.ExactArgs(1),
+		RunE: exportJSONSchema,
+	}
+
+	jsonSchemaCmd.Flags().String(outFlag, ".", "Specifies the output directory, one <event name>.schema.json file is written per published event")
+	addSchemaPathFlag(jsonSchemaCmd)
+	addInputFormatFlag(jsonSchemaCmd)
+
+	return jsonSchemaCmd
+}
+
+func exportJSONSchema(cmd *cobra.Command, args []string) error {
+	schemaResolver, err := decodeLifecycleFile(args[0], newDecodeOptions(cmd))
+	if err != nil {
+		annotateDecodeError(args[0], err)
+		return err
+	}
+
+	outDir, _ := cmd.Flags().GetString(outFlag)
+
+	paths, err := jsonschema.NewFileGenerator(outDir).Generate(schemaResolver)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	logger := log.New(os.Stderr, "", log.Lmicroseconds)
+	for i := range paths {
+		logger.Printf("document written: %s", paths[i])
+	}
+
+	return nil
+}
+
+func newExportAvroCommand() *cobra.Command {
+	avroCmd := &cobra.Command{
+		Use:   "avro [lifecycle.yaml file path]",
+		Short: "Export an Avro schema for each published event of the lifecycle.yaml file definition",
+		Long: `Export an Avro schema for each published event of the lifecycle.yaml file definition.
+
+Each schema is a record with the attributes and entities of the event. The objects are exported as records,
+the enums of strings as enums and the nullable definitions as unions with null. The records and enums of the
+declared types are defined once by schema and referenced by name after it. The definitions without an Avro
+equivalent, like enums of numbers or property names that are not valid Avro names, are reported as errors.`,
+		Args: cobra.ExactArgs(1),
+		RunE: exportAvro,
+	}
+
+	avroCmd.Flags().String(outFlag, ".", "Specifies the output directory, one <event name>.avsc file is written per published event")
+	avroCmd.Flags().String(namespaceFlag, "", "Specifies the namespace of the Avro schemas, like com.example.cakes")
+	addSchemaPathFlag(avroCmd)
+	addInputFormatFlag(avroCmd)
+
+	return avroCmd
+}
+
+func exportAvro(cmd *cobra.Command, args []string) error {
+	schemaResolver, err := decodeLifecycleFile(args[0], newDecodeOptions(cmd))
+	if err != nil {
+		annotateDecodeError(args[0], err)
+		return err
+	}
+
+	var (
+		outDir, _    = cmd.Flags().GetString(outFlag)
+		namespace, _ = cmd.Flags().GetString(namespaceFlag)
+	)
+
+	paths, err := avro.NewFileGenerator(outDir, namespace).Generate(schemaResolver)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	logger := log.New(os.Stderr, "", log.Lmicroseconds)
+	for i := range paths {
+		logger.Printf("document written: %s", paths[i])
+	}
+
+	return nil
+}
+
+// writeOutput writes the generated content to the file or to the standard output when the file is not specified
+func writeOutput(out string, write func(w io.Writer) error) error {
+	if len(out) < 1 {
+		return write(os.Stdout)
+	}
+
+	file, err := os.Create(out)
+	if err != nil {
+		return fmt.Errorf("can't create output file '%s': %w", out, err)
+	}
+
+	// The partially written file is removed, it would be mistaken for a valid output
+	if err := write(file); err != nil {
+		file.Close()
+		os.Remove(out)
+
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(out)
+
+		return fmt.Errorf("can't write output file '%s': %w", out, err)
+	}
+
+	log.New(os.Stderr, "", log.Lmicroseconds).Printf("file written: %s", out)
+	return nil
+}
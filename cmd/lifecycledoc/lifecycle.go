@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/git"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/asyncapi"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/json"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+	"github.com/spf13/cobra"
+)
+
+// expandLifecycleFilePaths expands the glob patterns of "args" keeping the specified order and removing duplicates
+func expandLifecycleFilePaths(args []string) ([]string, error) {
+	var (
+		paths []string
+		added = make(map[string]bool)
+	)
+
+	for _, arg := range args {
+		matches := []string{arg}
+
+		if strings.ContainsAny(arg, "*?[") {
+			var err error
+
+			matches, err = filepath.Glob(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid lifecycle file pattern '%s': %w", arg, err)
+			}
+
+			if len(matches) < 1 {
+				return nil, fmt.Errorf("no lifecycle file matches the pattern '%s'", arg)
+			}
+		}
+
+		for _, match := range matches {
+			if !added[match] {
+				added[match] = true
+				paths = append(paths, match)
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+const (
+	// inputFormatAuto detects the format of each lifecycle file by the file extension
+	inputFormatAuto = "auto"
+	inputFormatYAML = "yaml"
+	inputFormatJSON = "json"
+	// inputFormatAsyncAPI decodes AsyncAPI 2.x and 3.x documents, in the YAML or JSON formats
+	inputFormatAsyncAPI = "asyncapi"
+)
+
+// decodeOptions customizes the decoding of the lifecycle files
+type decodeOptions struct {
+	titlePrefix string
+	// strict reports unknown keywords in the lifecycle files
+	strict bool
+	// schemaPaths are the directories searched for the files of external references
+	schemaPaths []string
+	// inputFormat is the format of the lifecycle files. The files of external references are detected by the extension
+	inputFormat string
+	// readFile reads the files of external references, which are read from the file system when nil
+	readFile schema.FileReader
+}
+
+// newDecodeOptions reads the decoding options from the command flags. Undefined flags use the default values
+func newDecodeOptions(cmd *cobra.Command) decodeOptions {
+	titlePrefix, _ := cmd.Flags().GetString(titlePrefixFlag)
+	strict, _ := cmd.Flags().GetBool(strictFlag)
+	schemaPaths, _ := cmd.Flags().GetStringSlice(schemaPathFlag)
+
+	inputFormat, _ := cmd.Flags().GetString(inputFormatFlag)
+	if len(inputFormat) < 1 {
+		inputFormat = inputFormatAuto
+	}
+
+	return decodeOptions{
+		titlePrefix: titlePrefix,
+		strict:      strict,
+		schemaPaths: schemaPaths,
+		inputFormat: inputFormat,
+	}
+}
+
+// newDecoder creates the decoder of the lifecycle file "path" in the input format
+func newDecoder(inputFormat, path string, strict bool) (parser.Decoder, error) {
+	if inputFormat == inputFormatAuto {
+		inputFormat = inputFormatYAML
+
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			inputFormat = inputFormatJSON
+		}
+	}
+
+	switch inputFormat {
+	case inputFormatYAML:
+		if strict {
+			return yaml.NewStrictDecoder(), nil
+		}
+
+		return yaml.NewDecoder(), nil
+	case inputFormatJSON:
+		if strict {
+			return json.NewStrictDecoder(), nil
+		}
+
+		return json.NewDecoder(), nil
+	case inputFormatAsyncAPI:
+		// The AsyncAPI documents allow extensions, so there are no unknown keywords to report in the strict mode
+		return asyncapi.NewDecoder(), nil
+	}
+
+	return nil, fmt.Errorf("input format '%s' unknown", inputFormat)
+}
+
+// extensionDecoder decodes each file of external references with the decoder of the format detected by the file extension
+type extensionDecoder struct {
+	strict bool
+}
+
+func (e *extensionDecoder) Decode(r io.Reader, s parser.SchemaStorager) error {
+	var name string
+	if named, is := r.(interface{ Name() string }); is {
+		name = named.Name()
+	}
+
+	decoder, err := newDecoder(inputFormatAuto, name, e.strict)
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(r, s)
+}
+
+func decodeLifecycleFile(path string, options decodeOptions) (*schema.BasicResolver, error) {
+	lifecycleFile, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't open lifecycle file '%s': %w", path, err)
+	}
+
+	defer lifecycleFile.Close()
+
+	return decodeLifecycle(lifecycleFile, path, options)
+}
+
+// decodeLifecycleFileAtRef decodes the lifecycle file "path", and the files of its external references, as they were in
+// the git revision "ref"
+func decodeLifecycleFileAtRef(path, ref string, options decodeOptions) (*schema.BasicResolver, error) {
+	content, err := git.ShowFile(context.Background(), ref, path)
+	if err != nil {
+		return nil, err
+	}
+
+	options.readFile = func(path string) ([]byte, error) {
+		return git.ShowFile(context.Background(), ref, path)
+	}
+
+	return decodeLifecycle(
+		&namedReader{
+			Reader: bytes.NewReader(content),
+			name:   fmt.Sprintf("%s@%s", path, ref),
+		},
+		path,
+		options,
+	)
+}
+
+// namedReader identifies the source of the content in the decoding errors
+type namedReader struct {
+	io.Reader
+	name string
+}
+
+func (n *namedReader) Name() string {
+	return n.name
+}
+
+// decodeLifecycle decodes the lifecycle file "path" from the reader. External references are loaded relative to the "path"
+func decodeLifecycle(r io.Reader, path string, options decodeOptions) (*schema.BasicResolver, error) {
+	schameResolver := schema.NewBasicResolver()
+
+	// The format is detected by the "path", since the reader of git revisions is named "path@ref"
+	decoder, err := newDecoder(options.inputFormat, path, options.strict)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := schameResolver.SetDocumentPath(path); err != nil {
+		return nil, err
+	}
+
+	referenceLoader := schema.NewFileReferenceLoader(
+		&extensionDecoder{
+			strict: options.strict,
+		},
+		options.schemaPaths...,
+	)
+
+	if options.readFile != nil {
+		referenceLoader.SetFileReader(options.readFile)
+	}
+
+	schameResolver.SetReferenceLoader(referenceLoader)
+
+	if len(options.titlePrefix) > 0 {
+		schameResolver.SetConfluencePageTitlePrefix(options.titlePrefix)
+	}
+
+	if err := decoder.Decode(r, schameResolver); err != nil {
+		return nil, err
+	}
+
+	return schameResolver, nil
+}
@@ -2,7 +2,7 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -11,15 +11,20 @@ import (
 	"github.com/madeiramadeirabr/action-lifecycledoc/internal/config"
 	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/confluence"
 	confluenceRest "github.com/madeiramadeirabr/action-lifecycledoc/pkg/client/confluence"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/multierror"
 	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
-	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
 	"github.com/spf13/cobra"
-	goconfluence "github.com/virtomize/confluence-go-api"
 )
 
 const (
 	titlePrefixFlag  = "titlePrefix"
 	outputFormatFlag = "outputFormat"
+	dryRunFlag       = "dryRun"
+	outDirFlag       = "outDir"
+	baseRefFlag      = "baseRef"
+	strictFlag       = "strict"
+	schemaPathFlag   = "schemaPath"
+	inputFormatFlag  = "inputFormat"
 )
 
 var (
@@ -28,177 +33,246 @@ var (
 
 func init() {
 	errLog = log.New(os.Stderr, "", log.Lmicroseconds)
-
-	if err := config.LoadOrCreateConfigIfNotExists(); err != nil {
-		errLog.Fatal(err)
-	}
 }
 
 func main() {
 	rootCmd := &cobra.Command{
-		Use:   "lifecycledoc [lifecycle.yaml file path]",
+		Use:   "lifecycledoc [lifecycle.yaml file paths or globs]",
 		Short: "Create lifecycle documentation using lifecycle.yaml file definition",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MinimumNArgs(1),
 		RunE:  process,
+		// The errors of the lifecycle files and of the commands are not usage errors, silencing the usage of every command
+		SilenceUsage: true,
 	}
 
 	rootCmd.Flags().String(titlePrefixFlag, "", "Specifies a prefix for Confluence page titles")
-	rootCmd.Flags().String(outputFormatFlag, "cli", "Specifies the output format. Supported formats: cli, github-action-json, github-action-markdown")
+	rootCmd.Flags().String(outputFormatFlag, "cli", "Specifies the output format. Supported formats: cli, github-action-json, github-action-markdown, json")
+	rootCmd.Flags().Bool(dryRunFlag, false, "Writes the rendered Confluence storage body of each page to the output directory instead of publishing it")
+	rootCmd.Flags().String(outDirFlag, ".", "Specifies the output directory of the dry-run mode. Implies the dry-run mode when specified")
+	rootCmd.Flags().Bool(strictFlag, false, "Reports unknown keywords in the lifecycle files as errors")
+	addSchemaPathFlag(rootCmd)
+	addInputFormatFlag(rootCmd)
+
+	rootCmd.AddCommand(newValidateCommand())
+	rootCmd.AddCommand(newDiffCommand())
+	rootCmd.AddCommand(newCompatCommand())
+	rootCmd.AddCommand(newExportCommand())
+	rootCmd.AddCommand(newCodegenCommand())
 
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			if exitErr.err != nil {
+				errLog.Print(exitErr.err)
+			}
+
+			os.Exit(exitErr.code)
+		}
+
 		errLog.Fatal(err)
 	}
 }
 
+// exitCodeError allows commands to exit with a specific code
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit code %d", e.code)
+	}
+
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
 func process(cmd *cobra.Command, args []string) error {
-	var successWriter successResultWriter
+	var resultWriter resultWriter
 
 	switch format, _ := cmd.Flags().GetString(outputFormatFlag); format {
 	case "cli":
-		successWriter = newCLISuccessResultWriter()
+		resultWriter = newCLISuccessResultWriter()
 	case "github-action-json":
-		successWriter = newGithubJsonSuccessResultWriter()
+		resultWriter = newGithubJsonSuccessResultWriter()
 	case "github-action-markdown":
-		successWriter = newGithubMarkdownSuccessResultWriter()
+		resultWriter = newGithubMarkdownSuccessResultWriter()
+	case "json":
+		resultWriter = newJSONResultWriter()
 	default:
 		return fmt.Errorf("output format '%s' unknown", format)
 	}
 
-	lifecycleFile, err := os.Open(args[0])
+	paths, err := expandLifecycleFilePaths(args)
 	if err != nil {
-		return fmt.Errorf("can't open lifecycle YAML file '%s': %w", args[0], err)
+		return err
 	}
 
-	defer lifecycleFile.Close()
+	var (
+		options   = newDecodeOptions(cmd)
+		dryRun, _ = cmd.Flags().GetBool(dryRunFlag)
+		outDir, _ = cmd.Flags().GetString(outDirFlag)
 
-	schameResolver := schema.NewBasicResolver()
-	decoder := yaml.NewDecoder()
+		// generator is shared by all lifecycle files to setup the Confluence client only once
+		generator *confluence.Generator
+		errs      []error
+	)
 
-	titlePrefix, _ := cmd.Flags().GetString(titlePrefixFlag)
-	if len(titlePrefix) > 0 {
-		schameResolver.SetConfluencePageTitlePrefix(titlePrefix)
-	}
+	dryRun = dryRun || cmd.Flags().Changed(outDirFlag)
 
-	if err := decoder.Decode(lifecycleFile, schameResolver); err != nil {
-		return err
-	}
+	for _, path := range paths {
+		schameResolver, err := decodeLifecycleFile(path, options)
+		if err != nil {
+			annotateDecodeError(path, err)
+			// Decoding errors already contain the file path
+			errs = append(errs, multierror.Flatten(err)...)
+			resultWriter.AddResult(path, confluence.GenerateResult{Err: err})
+			continue
+		}
 
-	basicAuth := config.GetConfluenceBasicAuth()
+		resultWriter.AddSchema(path, schameResolver)
 
-	if len(basicAuth) < 1 {
-		basicAuth = confluenceRest.GenerateBasicAuthorization(
-			config.GetConfluenceEmail(),
-			config.GetConfluenceAPIKey(),
-		)
-	}
+		if dryRun {
+			results, err := writePagesToDir(outDir, schameResolver)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+				resultWriter.AddResult(path, confluence.GenerateResult{Err: err})
+				continue
+			}
 
-	generator := confluence.NewGenerator(
-		confluenceRest.NewClient(
-			http.DefaultClient,
-			config.GetConfluenceHost(),
-			basicAuth,
-		),
-		confluence.NewHTMLTemplateRetriver(),
-	)
+			for i := range results {
+				resultWriter.AddResult(path, results[i])
+			}
 
-	resultChan, err := generator.Generate(context.Background(), schameResolver)
-	if err != nil {
-		return err
-	}
+			continue
+		}
 
-	var lastErr error
+		if generator == nil {
+			generator, err = newConfluenceGenerator()
+			if err != nil {
+				return err
+			}
+		}
 
-	for result := range resultChan {
-		if result.Err != nil {
-			if lastErr != nil {
-				lastErr = fmt.Errorf("%s\n\t - %s", lastErr, result.Err)
-			} else {
-				lastErr = fmt.Errorf("\n\t - %s", result.Err)
+		resultChan, err := generator.Generate(context.Background(), schameResolver)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			resultWriter.AddResult(path, confluence.GenerateResult{Err: err})
+			continue
+		}
+
+		for result := range resultChan {
+			if result.Err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, result.Err))
 			}
-		} else {
-			successWriter.AddResult(result.Content)
+
+			resultWriter.AddResult(path, result)
 		}
 	}
 
-	if lastErr != nil {
-		return fmt.Errorf("the following errors occur when creating documentation pages: %s", lastErr)
+	// The results are written even on failures to allow scripts to check the status of each page
+	if err := resultWriter.Output(); err != nil {
+		return err
 	}
 
-	if err := successWriter.Output(); err != nil {
-		return err
+	if len(errs) > 0 {
+		return newErrorList("the following errors occur when creating documentation pages", errs)
 	}
 
 	return nil
 }
 
-type successResultWriter interface {
-	AddResult(content *goconfluence.Content)
-	Output() error
-}
-
-type cliSuccessResultWriter struct {
-	logger *log.Logger
+// addSchemaPathFlag adds the flag of the directories searched for the files of external references
+func addSchemaPathFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSlice(
+		schemaPathFlag,
+		nil,
+		"Specifies directories searched for the files of external references, after the directory of the referencing file",
+	)
 }
 
-func (c *cliSuccessResultWriter) AddResult(content *goconfluence.Content) {
-	c.logger.Printf("documentation generated: %s%s", content.Links.Base, content.Links.TinyUI)
+// addInputFormatFlag adds the flag of the format of the lifecycle files
+func addInputFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().String(
+		inputFormatFlag,
+		inputFormatAuto,
+		"Specifies the format of the lifecycle files. Supported formats: auto, yaml, json, asyncapi. The auto format detects JSON files by the .json extension",
+	)
 }
 
-func (*cliSuccessResultWriter) Output() error {
-	return nil
-}
+// newErrorList formats the errors as a list, one error per line
+func newErrorList(message string, errs []error) error {
+	var lastErr error
 
-func newCLISuccessResultWriter() *cliSuccessResultWriter {
-	return &cliSuccessResultWriter{
-		logger: log.New(os.Stdout, "", log.Lmicroseconds),
+	for i := range errs {
+		if lastErr != nil {
+			lastErr = fmt.Errorf("%s\n\t - %s", lastErr, errs[i])
+		} else {
+			lastErr = fmt.Errorf("\n\t - %s", errs[i])
+		}
 	}
-}
 
-type githubSuccessResultWriter struct {
-	links []string
+	return fmt.Errorf("%s: %s", message, lastErr)
 }
 
-func (g *githubSuccessResultWriter) AddResult(content *goconfluence.Content) {
-	g.links = append(g.links, fmt.Sprintf("%s%s", content.Links.Base, content.Links.TinyUI))
-}
-
-type githubJsonSuccessResultWriter struct {
-	*githubSuccessResultWriter
-}
+func newConfluenceGenerator() (*confluence.Generator, error) {
+	if err := config.LoadOrCreateConfigIfNotExists(); err != nil {
+		return nil, err
+	}
 
-// Output write Confluence page links in GitHub output format
-// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#setting-an-output-parameter
-func (g *githubJsonSuccessResultWriter) Output() error {
-	fmt.Print("links=")
+	basicAuth := config.GetConfluenceBasicAuth()
 
-	if err := json.NewEncoder(os.Stdout).Encode(g.links); err != nil {
-		return fmt.Errorf("can't write links json: %w", err)
+	if len(basicAuth) < 1 {
+		basicAuth = confluenceRest.GenerateBasicAuthorization(
+			config.GetConfluenceEmail(),
+			config.GetConfluenceAPIKey(),
+		)
 	}
 
-	return nil
+	return confluence.NewGenerator(
+		confluenceRest.NewClient(
+			http.DefaultClient,
+			config.GetConfluenceHost(),
+			basicAuth,
+		),
+		confluence.NewHTMLTemplateRetriver(),
+	), nil
 }
 
-func newGithubJsonSuccessResultWriter() *githubJsonSuccessResultWriter {
-	return &githubJsonSuccessResultWriter{
-		githubSuccessResultWriter: &githubSuccessResultWriter{},
+// writePagesToDir writes the pages to files, logging the written files to stderr since stdout has the command output
+func writePagesToDir(outDir string, schemaResolver schema.Resolver) ([]confluence.GenerateResult, error) {
+	confluencePages, err := schemaResolver.GetConfluence()
+	if err != nil {
+		return nil, err
 	}
-}
 
-type githubMarkdownSuccessResultWriter struct {
-	*githubSuccessResultWriter
-}
+	generator := confluence.NewFileGenerator(outDir, confluence.NewHTMLTemplateRetriver())
 
-func (g *githubMarkdownSuccessResultWriter) Output() error {
-	fmt.Print("## Updated Documentation(s)\n")
-	for i := range g.links {
-		fmt.Printf(" - %s\n", g.links[i])
+	paths, err := generator.Generate(schemaResolver)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
-}
+	var (
+		logger  = log.New(os.Stderr, "", log.Lmicroseconds)
+		pages   = confluencePages.Pages()
+		results = make([]confluence.GenerateResult, len(paths))
+	)
+
+	// The files are written in the declaration order of the pages
+	for i := range paths {
+		logger.Printf("documentation written: %s", paths[i])
 
-func newGithubMarkdownSuccessResultWriter() *githubMarkdownSuccessResultWriter {
-	return &githubMarkdownSuccessResultWriter{
-		githubSuccessResultWriter: &githubSuccessResultWriter{},
+		results[i] = confluence.GenerateResult{
+			Page:   pages[i],
+			Action: confluence.GenerateActionWritten,
+			File:   paths[i],
+		}
 	}
+
+	return results, nil
 }
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/confluence"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+)
+
+type resultWriter interface {
+	// AddSchema adds the decoded schema of the lifecycle file "path"
+	AddSchema(path string, schemaResolver schema.Resolver)
+	// AddResult adds the result of a page generated from the lifecycle file "path"
+	AddResult(path string, result confluence.GenerateResult)
+	Output() error
+}
+
+type cliSuccessResultWriter struct {
+	logger *log.Logger
+}
+
+func (c *cliSuccessResultWriter) AddResult(path string, result confluence.GenerateResult) {
+	// The written files are already logged by the dry-run mode
+	if result.Err != nil || result.Content == nil {
+		return
+	}
+
+	if result.Action == confluence.GenerateActionUnchanged {
+		c.logger.Printf("documentation unchanged: %s%s", result.Content.Links.Base, result.Content.Links.TinyUI)
+		return
+	}
+
+	c.logger.Printf("documentation generated: %s%s", result.Content.Links.Base, result.Content.Links.TinyUI)
+}
+
+func (*cliSuccessResultWriter) AddSchema(path string, schemaResolver schema.Resolver) {}
+
+func (*cliSuccessResultWriter) Output() error {
+	return nil
+}
+
+func newCLISuccessResultWriter() *cliSuccessResultWriter {
+	return &cliSuccessResultWriter{
+		logger: log.New(os.Stdout, "", log.Lmicroseconds),
+	}
+}
+
+type githubSuccessResultWriter struct {
+	links []string
+	// updatedLinks are the links of the created and updated pages, excluding the unchanged ones
+	updatedLinks []string
+	schemas      []lifecycleSchema
+}
+
+type lifecycleSchema struct {
+	path           string
+	schemaResolver schema.Resolver
+}
+
+func (g *githubSuccessResultWriter) AddSchema(path string, schemaResolver schema.Resolver) {
+	g.schemas = append(g.schemas, lifecycleSchema{
+		path:           path,
+		schemaResolver: schemaResolver,
+	})
+}
+
+func (g *githubSuccessResultWriter) AddResult(path string, result confluence.GenerateResult) {
+	// The pages written in the dry-run mode have no links
+	if result.Err != nil || result.Content == nil {
+		return
+	}
+
+	link := fmt.Sprintf("%s%s", result.Content.Links.Base, result.Content.Links.TinyUI)
+	g.links = append(g.links, link)
+
+	if result.Action != confluence.GenerateActionUnchanged {
+		g.updatedLinks = append(g.updatedLinks, link)
+	}
+}
+
+type githubJsonSuccessResultWriter struct {
+	*githubSuccessResultWriter
+}
+
+// Output write Confluence page links in the file of GitHub output parameters
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#setting-an-output-parameter
+func (g *githubJsonSuccessResultWriter) Output() error {
+	links, err := json.Marshal(g.links)
+	if err != nil {
+		return fmt.Errorf("can't write links json: %w", err)
+	}
+
+	delimiter, err := newGithubDelimiter()
+	if err != nil {
+		return err
+	}
+
+	// The delimiter syntax keeps the output safe even if the value has multiple lines
+	return appendToGithubFile(
+		"GITHUB_OUTPUT",
+		fmt.Sprintf("links<<%s\n%s\n%s\n", delimiter, links, delimiter),
+	)
+}
+
+func newGithubJsonSuccessResultWriter() *githubJsonSuccessResultWriter {
+	return &githubJsonSuccessResultWriter{
+		githubSuccessResultWriter: &githubSuccessResultWriter{},
+	}
+}
+
+type githubMarkdownSuccessResultWriter struct {
+	*githubSuccessResultWriter
+}
+
+// Output write Confluence page links and a summary of the events and types in the job summary
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#adding-a-job-summary
+func (g *githubMarkdownSuccessResultWriter) Output() error {
+	out := &strings.Builder{}
+
+	out.WriteString("## Updated Documentation(s)\n")
+	for i := range g.updatedLinks {
+		fmt.Fprintf(out, " - %s\n", g.updatedLinks[i])
+	}
+
+	for i := range g.schemas {
+		g.writeSchemaSummary(out, g.schemas[i])
+	}
+
+	return appendToGithubFile("GITHUB_STEP_SUMMARY", out.String())
+}
+
+func (g *githubMarkdownSuccessResultWriter) writeSchemaSummary(out *strings.Builder, lifecycle lifecycleSchema) {
+	// Invalid schemas are reported as errors by the generation, there is nothing to summarize
+	publishedEvents, err := lifecycle.schemaResolver.GetPublishedEvents()
+	if err != nil {
+		return
+	}
+
+	consumedEvents, err := lifecycle.schemaResolver.GetConsumedEvents()
+	if err != nil {
+		return
+	}
+
+	typesDefinitions, err := lifecycle.schemaResolver.GetTypes()
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintf(out, "\n### %s\n", escapeMarkdownTableCell(lifecycle.path))
+
+	if len(publishedEvents) > 0 {
+		out.WriteString("\n| Published event | Visibility | Module | Description |\n| --- | --- | --- | --- |\n")
+		for i := range publishedEvents {
+			fmt.Fprintf(
+				out,
+				"| `%s` | %s | %s | %s |\n",
+				publishedEvents[i].Name(),
+				publishedEvents[i].Visibility(),
+				escapeMarkdownTableCell(publishedEvents[i].Module()),
+				escapeMarkdownTableCell(publishedEvents[i].Description()),
+			)
+		}
+	}
+
+	if len(consumedEvents) > 0 {
+		out.WriteString("\n| Consumed event | Description |\n| --- | --- |\n")
+		for i := range consumedEvents {
+			fmt.Fprintf(
+				out,
+				"| `%s` | %s |\n",
+				consumedEvents[i].Name(),
+				escapeMarkdownTableCell(consumedEvents[i].Description()),
+			)
+		}
+	}
+
+	if len(typesDefinitions) > 0 {
+		out.WriteString("\n| Type | Kind | Description |\n| --- | --- | --- |\n")
+		for i := range typesDefinitions {
+			fmt.Fprintf(
+				out,
+				"| `%s` | `%s` | %s |\n",
+				typesDefinitions[i].Name(),
+				typesDefinitions[i].Type(),
+				escapeMarkdownTableCell(typesDefinitions[i].Description()),
+			)
+		}
+	}
+}
+
+func newGithubMarkdownSuccessResultWriter() *githubMarkdownSuccessResultWriter {
+	return &githubMarkdownSuccessResultWriter{
+		githubSuccessResultWriter: &githubSuccessResultWriter{},
+	}
+}
+
+// appendToGithubFile appends the content to the file specified by the GitHub environment variable.
+// The content is written to stdout when the variable is not defined, for example, outside GitHub runners
+func appendToGithubFile(envName, content string) error {
+	path := os.Getenv(envName)
+	if len(path) < 1 {
+		_, err := fmt.Fprint(os.Stdout, content)
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("can't open %s file '%s': %w", envName, path, err)
+	}
+
+	defer file.Close()
+
+	if _, err := file.WriteString(content); err != nil {
+		return fmt.Errorf("can't write to %s file '%s': %w", envName, path, err)
+	}
+
+	return nil
+}
+
+func newGithubDelimiter() (string, error) {
+	randomBytes := make([]byte, 16)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", fmt.Errorf("can't generate GitHub output delimiter: %w", err)
+	}
+
+	return fmt.Sprintf("ghadelimiter_%s", hex.EncodeToString(randomBytes)), nil
+}
+
+func escapeMarkdownTableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+type jsonResult struct {
+	File       string `json:"file"`
+	Title      string `json:"title,omitempty"`
+	SpaceKey   string `json:"spaceKey,omitempty"`
+	AncestorID string `json:"ancestorId,omitempty"`
+	PageID     string `json:"pageId,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Version    int    `json:"version,omitempty"`
+	Action     string `json:"action,omitempty"`
+	OutFile    string `json:"outFile,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// jsonResultWriter writes the status of each page, including the failed ones, as a JSON array
+type jsonResultWriter struct {
+	results []jsonResult
+}
+
+func (*jsonResultWriter) AddSchema(path string, schemaResolver schema.Resolver) {}
+
+func (j *jsonResultWriter) AddResult(path string, result confluence.GenerateResult) {
+	out := jsonResult{
+		File:    path,
+		Action:  string(result.Action),
+		OutFile: result.File,
+	}
+
+	if result.Page != nil {
+		out.Title = result.Page.Title()
+		out.SpaceKey = result.Page.SpaceKey()
+		out.AncestorID = result.Page.AncestorID()
+	}
+
+	if result.Content != nil {
+		out.PageID = result.Content.ID
+
+		if result.Content.Links != nil {
+			out.URL = fmt.Sprintf("%s%s", result.Content.Links.Base, result.Content.Links.TinyUI)
+		}
+
+		if result.Content.Version != nil {
+			out.Version = result.Content.Version.Number
+		}
+	}
+
+	if result.Err != nil {
+		out.Error = result.Err.Error()
+	}
+
+	j.results = append(j.results, out)
+}
+
+func (j *jsonResultWriter) Output() error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	// Always write an array, even without results
+	results := j.results
+	if results == nil {
+		results = []jsonResult{}
+	}
+
+	if err := encoder.Encode(results); err != nil {
+		return fmt.Errorf("can't write json results: %w", err)
+	}
+
+	return nil
+}
+
+func newJSONResultWriter() *jsonResultWriter {
+	return &jsonResultWriter{}
+}
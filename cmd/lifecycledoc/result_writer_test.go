@@ -0,0 +1,323 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/confluence"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+	goconfluence "github.com/virtomize/confluence-go-api"
+)
+
+func TestShouldWriteJSONResults(t *testing.T) {
+	page, err := types.NewConfluencePage("Eventos: bolos", "SPACEKEY", "123456789")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		results  []confluence.GenerateResult
+		expected []jsonResult
+	}{
+		{
+			name:     "should write an empty array without results",
+			expected: []jsonResult{},
+		},
+		{
+			name: "should write the status of each page",
+			results: []confluence.GenerateResult{
+				{
+					Page:    page,
+					Content: newContent("98765", "/x/created", 2),
+					Action:  confluence.GenerateActionCreated,
+				},
+				{
+					Page:   page,
+					Action: confluence.GenerateActionWritten,
+					File:   "out/Eventos_ bolos.html",
+				},
+				{
+					Page: page,
+					Err:  errors.New("can't update page"),
+				},
+			},
+			expected: []jsonResult{
+				{
+					File:       "lifecycle.yaml",
+					Title:      "Eventos: bolos",
+					SpaceKey:   "SPACEKEY",
+					AncestorID: "123456789",
+					PageID:     "98765",
+					URL:        "https://confluence.example.com/x/created",
+					Version:    2,
+					Action:     "created",
+				},
+				{
+					File:       "lifecycle.yaml",
+					Title:      "Eventos: bolos",
+					SpaceKey:   "SPACEKEY",
+					AncestorID: "123456789",
+					Action:     "written",
+					OutFile:    "out/Eventos_ bolos.html",
+				},
+				{
+					File:       "lifecycle.yaml",
+					Title:      "Eventos: bolos",
+					SpaceKey:   "SPACEKEY",
+					AncestorID: "123456789",
+					Error:      "can't update page",
+				},
+			},
+		},
+		{
+			name: "should write the decoding errors without page",
+			results: []confluence.GenerateResult{
+				{Err: errors.New("lifecycle.yaml:3:1: invalid definition")},
+			},
+			expected: []jsonResult{
+				{File: "lifecycle.yaml", Error: "lifecycle.yaml:3:1: invalid definition"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			writer := newJSONResultWriter()
+			for i := range testCase.results {
+				writer.AddResult("lifecycle.yaml", testCase.results[i])
+			}
+
+			output := captureOutput(t, &os.Stdout, func() {
+				if err := writer.Output(); err != nil {
+					t.Fatal(err)
+				}
+			})
+
+			var results []jsonResult
+			if err := json.Unmarshal([]byte(output), &results); err != nil {
+				t.Fatalf("can't decode '%s': %s", output, err)
+			}
+
+			if !reflect.DeepEqual(results, testCase.expected) {
+				t.Errorf("expected '%+v', received '%+v'", testCase.expected, results)
+			}
+		})
+	}
+}
+
+func TestShouldWriteGithubOutputs(t *testing.T) {
+	schemaResolver := schema.NewBasicResolver()
+	if err := yaml.NewDecoder().Decode(strings.NewReader(`
+version: "1.0"
+name: super-cool-service
+
+events:
+  published:
+    CAKE_BURNED:
+      visibility: public
+      module: cooker
+      description: Bolo | queimado
+      attributes:
+        $ref: '#/types/Cake'
+      entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+            value: "12354"
+
+  consumed:
+    CAKE_PURCHASED:
+      description: Usado para fazer o bolo
+
+types:
+  Cake:
+    type: object
+    description: Um bolo
+    properties:
+      layers:
+        type: integer
+        value: 5`), schemaResolver); err != nil {
+		t.Fatal(err)
+	}
+
+	results := []confluence.GenerateResult{
+		{Content: newContent("1", "/x/created", 1), Action: confluence.GenerateActionCreated},
+		{Content: newContent("2", "/x/unchanged", 3), Action: confluence.GenerateActionUnchanged},
+		{Action: confluence.GenerateActionWritten, File: "out/page.html"},
+		{Err: errors.New("can't update page")},
+	}
+
+	testCases := []struct {
+		name      string
+		envName   string
+		newWriter func() resultWriter
+		expected  string
+	}{
+		{
+			name:      "should write the links as json",
+			envName:   "GITHUB_OUTPUT",
+			newWriter: func() resultWriter { return newGithubJsonSuccessResultWriter() },
+			expected: `links<<DELIMITER
+["https://confluence.example.com/x/created","https://confluence.example.com/x/unchanged"]
+DELIMITER
+`,
+		},
+		{
+			name:      "should write the updated links and the schema summary as markdown",
+			envName:   "GITHUB_STEP_SUMMARY",
+			newWriter: func() resultWriter { return newGithubMarkdownSuccessResultWriter() },
+			expected: `## Updated Documentation(s)
+ - https://confluence.example.com/x/created
+
+### lifecycle.yaml
+
+| Published event | Visibility | Module | Description |
+| --- | --- | --- | --- |
+| ` + "`CAKE_BURNED`" + ` | public | cooker | Bolo \| queimado |
+
+| Consumed event | Description |
+| --- | --- |
+| ` + "`CAKE_PURCHASED`" + ` | Usado para fazer o bolo |
+
+| Type | Kind | Description |
+| --- | --- | --- |
+| ` + "`Cake` | `object`" + ` | Um bolo |
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		newOutput := func(t *testing.T) string {
+			writer := testCase.newWriter()
+			writer.AddSchema("lifecycle.yaml", schemaResolver)
+
+			for i := range results {
+				writer.AddResult("lifecycle.yaml", results[i])
+			}
+
+			return captureOutput(t, &os.Stdout, func() {
+				if err := writer.Output(); err != nil {
+					t.Fatal(err)
+				}
+			})
+		}
+
+		t.Run(testCase.name+" in the file of the environment variable", func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "github")
+			t.Setenv(testCase.envName, path)
+
+			// The file is shared by the steps of the job, so the content must be appended
+			const previous = "previous=value\n"
+			if err := os.WriteFile(path, []byte(previous), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if stdout := newOutput(t); len(stdout) > 0 {
+				t.Errorf("expected no output in stdout, received '%s'", stdout)
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertGithubOutput(t, previous+testCase.expected, string(content))
+		})
+
+		t.Run(testCase.name+" in stdout without the environment variable", func(t *testing.T) {
+			t.Setenv(testCase.envName, "")
+
+			assertGithubOutput(t, testCase.expected, newOutput(t))
+		})
+	}
+}
+
+func TestShouldUseRandomGithubOutputDelimiter(t *testing.T) {
+	t.Setenv("GITHUB_OUTPUT", "")
+
+	delimiters := make(map[string]bool)
+
+	for i := 0; i < 2; i++ {
+		output := captureOutput(t, &os.Stdout, func() {
+			if err := newGithubJsonSuccessResultWriter().Output(); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		matches := githubDelimiterRegex.FindAllString(output, -1)
+		if len(matches) != 2 || matches[0] != matches[1] {
+			t.Fatalf("expected the same delimiter opening and closing the output, received '%s'", output)
+		}
+
+		delimiters[matches[0]] = true
+	}
+
+	if len(delimiters) != 2 {
+		t.Errorf("expected a different delimiter in each output, received '%v'", delimiters)
+	}
+}
+
+var githubDelimiterRegex = regexp.MustCompile(`ghadelimiter_[0-9a-f]{32}`)
+
+// assertGithubOutput compares the outputs replacing the random delimiters by "DELIMITER"
+func assertGithubOutput(t *testing.T, expected, received string) {
+	t.Helper()
+
+	if received = githubDelimiterRegex.ReplaceAllString(received, "DELIMITER"); received != expected {
+		t.Errorf("expected '%s', received '%s'", expected, received)
+	}
+}
+
+func newContent(id, tinyUI string, version int) *goconfluence.Content {
+	return &goconfluence.Content{
+		ID:      id,
+		Version: &goconfluence.Version{Number: version},
+		Links: &goconfluence.Links{
+			Base:   "https://confluence.example.com",
+			TinyUI: tinyUI,
+		},
+	}
+}
+
+// captureOutput returns what "fn" writes to the "output" file, like os.Stdout
+func captureOutput(t *testing.T, output **os.File, fn func()) string {
+	t.Helper()
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "output"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	original := *output
+	*output = file
+
+	defer func() {
+		*output = original
+	}()
+
+	fn()
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(content)
+}
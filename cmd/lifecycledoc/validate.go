@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/confluence"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/multierror"
+	"github.com/spf13/cobra"
+)
+
+func newValidateCommand() *cobra.Command {
+	validateCmd := &cobra.Command{
+		Use:   "validate [lifecycle.yaml file paths or globs]",
+		Short: "Validate the lifecycle.yaml file definition without contacting Confluence",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  validate,
+	}
+
+	validateCmd.Flags().Bool(strictFlag, true, "Reports unknown keywords in the lifecycle files, such as typos of optional keywords")
+	addSchemaPathFlag(validateCmd)
+	addInputFormatFlag(validateCmd)
+
+	return validateCmd
+}
+
+func validate(cmd *cobra.Command, args []string) error {
+	paths, err := expandLifecycleFilePaths(args)
+	if err != nil {
+		return err
+	}
+
+	var (
+		logger = log.New(os.Stdout, "", log.Lmicroseconds)
+		errs   []error
+	)
+
+	for _, path := range paths {
+		if problems := validateLifecycleFile(path, newDecodeOptions(cmd)); len(problems) > 0 {
+			errs = append(errs, problems...)
+			continue
+		}
+
+		logger.Printf("lifecycle file '%s' is valid", path)
+	}
+
+	if len(errs) > 0 {
+		return newErrorList("the following problems were found in the lifecycle files", errs)
+	}
+
+	return nil
+}
+
+// validateLifecycleFile returns the problems found in the lifecycle file prefixed with its path
+func validateLifecycleFile(path string, options decodeOptions) []error {
+	schemaResolver, err := decodeLifecycleFile(path, options)
+	if err != nil {
+		annotateDecodeError(path, err)
+		// Decoding errors already contain the file path
+		return multierror.Flatten(err)
+	}
+
+	var problems []error
+
+	if _, err := schemaResolver.GetTypes(); err != nil {
+		problems = appendProblemsIfNew(problems, err)
+	}
+
+	if _, err := schemaResolver.GetPublishedEvents(); err != nil {
+		problems = appendProblemsIfNew(problems, err)
+	}
+
+	if _, err := schemaResolver.GetConsumedEvents(); err != nil {
+		problems = appendProblemsIfNew(problems, err)
+	}
+
+	annotateResolutionErrors(path, problems)
+
+	// The template depends on the resolved schema, so rendering it would only repeat the problems above
+	if len(problems) < 1 {
+		templateWriter := confluence.NewTemplateWriter(confluence.NewHTMLTemplateRetriver())
+
+		if err := templateWriter.Write(io.Discard, schemaResolver); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	for i := range problems {
+		problems[i] = fmt.Errorf("%s: %w", path, problems[i])
+	}
+
+	return problems
+}
+
+// appendProblemsIfNew prevents reporting the same resolution error more than once
+func appendProblemsIfNew(problems []error, err error) []error {
+	for _, err := range multierror.Flatten(err) {
+		if !containsProblem(problems, err) {
+			problems = append(problems, err)
+		}
+	}
+
+	return problems
+}
+
+func containsProblem(problems []error, err error) bool {
+	for i := range problems {
+		if problems[i].Error() == err.Error() {
+			return true
+		}
+	}
+
+	return false
+}
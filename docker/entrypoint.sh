@@ -1,7 +1,4 @@
 #!/bin/sh -l
 
-if [ $1 = 'github-action-json' ]; then
-    lifecycledoc --outputFormat github-action-json --titlePrefix $2 $3 >> $GITHUB_OUTPUT
-else
-    lifecycledoc --outputFormat $1 --titlePrefix $2 $3 >> $GITHUB_STEP_SUMMARY
-fi
\ No newline at end of file
+# The GitHub output formats write directly to the files specified by $GITHUB_OUTPUT and $GITHUB_STEP_SUMMARY
+lifecycledoc --outputFormat "$1" --titlePrefix "$2" $3
\ No newline at end of file
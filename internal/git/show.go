@@ -0,0 +1,49 @@
+// git package reads files from the repository history using the local git binary
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ShowFile returns the content of the file "path" at the revision "ref"
+func ShowFile(ctx context.Context, ref, path string) ([]byte, error) {
+	if len(ref) < 1 {
+		return nil, fmt.Errorf("the git ref cannot be empty")
+	}
+
+	// git would read the ref as an option
+	if strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("the git ref '%s' cannot start with '-'", ref)
+	}
+
+	var (
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+	)
+
+	// The "./" prefix makes git resolve the path relative to the working directory instead of the repository root
+	cmd := exec.CommandContext(
+		ctx,
+		"git",
+		"-C", filepath.Dir(path),
+		"show",
+		fmt.Sprintf("%s:./%s", ref, filepath.Base(path)),
+	)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); len(message) > 0 {
+			return nil, fmt.Errorf("can't read '%s' at git ref '%s': %s", path, ref, message)
+		}
+
+		return nil, fmt.Errorf("can't read '%s' at git ref '%s': %w", path, ref, err)
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,73 @@
+package git_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/git"
+)
+
+func TestShouldShowFileAtRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not found")
+	}
+
+	repositoryDir := t.TempDir()
+	filePath := filepath.Join(repositoryDir, "docs", "lifecycle.yaml")
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	runGit(t, repositoryDir, "init", "--quiet")
+
+	writeFile(t, filePath, "version: old")
+	runGit(t, repositoryDir, "add", "--all")
+	runGit(t, repositoryDir, "commit", "--quiet", "--message", "old")
+
+	writeFile(t, filePath, "version: new")
+
+	content, err := git.ShowFile(context.Background(), "HEAD", filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "version: old"; string(content) != expected {
+		t.Errorf("expected '%s', received '%s'", expected, content)
+	}
+
+	if _, err := git.ShowFile(context.Background(), "unknown-ref", filePath); err == nil {
+		t.Error("expected error for unknown ref, received nil")
+	}
+}
+
+func TestShouldRejectRefsStartingWithDash(t *testing.T) {
+	_, err := git.ShowFile(context.Background(), "--output=/tmp/lifecycle.yaml", "lifecycle.yaml")
+
+	expected := "the git ref '--output=/tmp/lifecycle.yaml' cannot start with '-'"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error '%s', received '%v'", expected, err)
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@test.com"}, args...)...)
+	cmd.Dir = dir
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("can't run git %v: %s: %s", args, err, output)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,279 @@
+// asyncapi package writes the resolved lifecycle definitions as an AsyncAPI 3.0 document
+package asyncapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/jsonschema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/jsonc"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	asyncAPIVersion = "3.0.0"
+	schemasPath     = "#/components/schemas/"
+)
+
+const (
+	FormatYAML Format = iota
+	FormatJSON
+)
+
+// Format of the written document
+type Format uint8
+
+/*
+DocumentWriter writes the AsyncAPI document of the lifecycle definitions. The published events are "send" operations
+and the consumed events are "receive" operations, each one with its own channel named by the event. The visibility
+and module of the published events are kept in the "x-visibility" and "x-module" extensions of the messages
+*/
+type DocumentWriter struct {
+	// version of the application API, the "info.version" of the document
+	version string
+	format  Format
+}
+
+func NewDocumentWriter(version string, format Format) *DocumentWriter {
+	return &DocumentWriter{
+		version: version,
+		format:  format,
+	}
+}
+
+func (d *DocumentWriter) Write(w io.Writer, schemaResolver schema.Resolver) error {
+	document, err := d.document(schemaResolver)
+	if err != nil {
+		return err
+	}
+
+	if d.format == FormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(document); err != nil {
+			return fmt.Errorf("can't encode asyncapi document: %w", err)
+		}
+
+		return nil
+	}
+
+	node, err := yamlNode(document)
+	if err != nil {
+		return fmt.Errorf("can't encode asyncapi document: %w", err)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(node); err != nil {
+		return fmt.Errorf("can't encode asyncapi document: %w", err)
+	}
+
+	return encoder.Close()
+}
+
+func (d *DocumentWriter) document(schemaResolver schema.Resolver) (jsonc.MapSlice, error) {
+	typesDefinitions, err := schemaResolver.GetTypes()
+	if err != nil {
+		return nil, fmt.Errorf("can't get types to write: %w", err)
+	}
+
+	publishedEvents, err := schemaResolver.GetPublishedEvents()
+	if err != nil {
+		return nil, fmt.Errorf("can't get published events to write: %w", err)
+	}
+
+	consumedEvents, err := schemaResolver.GetConsumedEvents()
+	if err != nil {
+		return nil, fmt.Errorf("can't get consumed events to write: %w", err)
+	}
+
+	project, err := schemaResolver.GetProject()
+	if err != nil {
+		return nil, fmt.Errorf("can't get project to write: %w", err)
+	}
+
+	converter := jsonschema.NewConverter(schemasPath, typesDefinitions)
+
+	schemas, err := converter.Definitions()
+	if err != nil {
+		return nil, fmt.Errorf("can't convert types: %w", err)
+	}
+
+	var (
+		channels   jsonc.MapSlice
+		operations jsonc.MapSlice
+		messages   jsonc.MapSlice
+	)
+
+	for _, event := range publishedEvents {
+		payload, err := converter.PublishedEventSchema(event)
+		if err != nil {
+			return nil, fmt.Errorf("can't convert published event '%s': %w", event.Name(), err)
+		}
+
+		message := jsonc.MapSlice{{Key: "name", Value: event.Name()}}
+		message = appendNotEmpty(message, "description", event.Description())
+		message = append(message, jsonc.MapItem{Key: "x-visibility", Value: event.Visibility().String()})
+		message = appendNotEmpty(message, "x-module", event.Module())
+		message = append(message, jsonc.MapItem{Key: "payload", Value: payload})
+
+		messages = append(messages, jsonc.MapItem{Key: event.Name(), Value: message})
+		channels, operations = d.appendOperation(channels, operations, event.Name(), "send")
+	}
+
+	for _, event := range consumedEvents {
+		message := jsonc.MapSlice{{Key: "name", Value: event.Name()}}
+		message = appendNotEmpty(message, "description", event.Description())
+
+		// The same event can be published and consumed by the application
+		if !containsKey(messages, event.Name()) {
+			messages = append(messages, jsonc.MapItem{Key: event.Name(), Value: message})
+		}
+
+		channels, operations = d.appendOperation(channels, operations, event.Name(), "receive")
+	}
+
+	info := jsonc.MapSlice{
+		{Key: "title", Value: project.Name()},
+		{Key: "version", Value: d.version},
+	}
+
+	document := jsonc.MapSlice{
+		{Key: "asyncapi", Value: asyncAPIVersion},
+		{Key: "info", Value: info},
+	}
+
+	if pages := confluencePages(project.Confluence()); len(pages) > 0 {
+		document = append(document, jsonc.MapItem{Key: "x-confluence", Value: jsonc.MapSlice{{Key: "pages", Value: pages}}})
+	}
+
+	document = appendNotEmpty(document, "channels", channels)
+	document = appendNotEmpty(document, "operations", operations)
+
+	var components jsonc.MapSlice
+	components = appendNotEmpty(components, "schemas", schemas)
+	components = appendNotEmpty(components, "messages", messages)
+
+	return appendNotEmpty(document, "components", components), nil
+}
+
+// appendOperation appends the operation of the event with the "action" and its channel, when it doesn't exist
+func (d *DocumentWriter) appendOperation(channels, operations jsonc.MapSlice, eventName, action string) (jsonc.MapSlice, jsonc.MapSlice) {
+	channelPath := "#/channels/" + escapePointer(eventName)
+
+	if !containsKey(channels, eventName) {
+		channels = append(channels, jsonc.MapItem{
+			Key: eventName,
+			Value: jsonc.MapSlice{
+				{Key: "address", Value: eventName},
+				{
+					Key: "messages",
+					Value: jsonc.MapSlice{
+						{Key: eventName, Value: jsonc.MapSlice{{Key: "$ref", Value: "#/components/messages/" + escapePointer(eventName)}}},
+					},
+				},
+			},
+		})
+	}
+
+	operations = append(operations, jsonc.MapItem{
+		Key: fmt.Sprintf("%s.%s", eventName, action),
+		Value: jsonc.MapSlice{
+			{Key: "action", Value: action},
+			{Key: "channel", Value: jsonc.MapSlice{{Key: "$ref", Value: channelPath}}},
+			{
+				Key: "messages",
+				Value: []interface{}{
+					jsonc.MapSlice{{Key: "$ref", Value: fmt.Sprintf("%s/messages/%s", channelPath, escapePointer(eventName))}},
+				},
+			},
+		},
+	})
+
+	return channels, operations
+}
+
+func confluencePages(confluence *types.Confluence) []interface{} {
+	var pages []interface{}
+
+	for _, page := range confluence.Pages() {
+		pages = append(pages, jsonc.MapSlice{
+			{Key: "title", Value: page.Title()},
+			{Key: "spaceKey", Value: page.SpaceKey()},
+			{Key: "ancestorId", Value: page.AncestorID()},
+		})
+	}
+
+	return pages
+}
+
+// appendNotEmpty appends the value when it is not empty, like the optional keywords without value
+func appendNotEmpty[T string | jsonc.MapSlice](m jsonc.MapSlice, key string, value T) jsonc.MapSlice {
+	if len(value) < 1 {
+		return m
+	}
+
+	return append(m, jsonc.MapItem{Key: key, Value: value})
+}
+
+func containsKey(m jsonc.MapSlice, key string) bool {
+	for i := range m {
+		if m[i].Key == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// escapePointer escapes the token of JSON pointers, like event names with slashes
+func escapePointer(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
+
+// yamlNode converts the document to a YAML node, keeping the order of the keys
+func yamlNode(value interface{}) (*yaml.Node, error) {
+	switch value := value.(type) {
+	case jsonc.MapSlice:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+
+		for i := range value {
+			itemNode, err := yamlNode(value[i].Value)
+			if err != nil {
+				return nil, err
+			}
+
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value[i].Key}, itemNode)
+		}
+
+		return node, nil
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+
+		for i := range value {
+			itemNode, err := yamlNode(value[i])
+			if err != nil {
+				return nil, err
+			}
+
+			node.Content = append(node.Content, itemNode)
+		}
+
+		return node, nil
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
@@ -0,0 +1,223 @@
+package asyncapi_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/asyncapi"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	asyncapiParser "github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/asyncapi"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+)
+
+const lifecycleDefinition = `
+version: "1.0"
+name: super-cool-service
+
+confluence:
+  pages:
+    - spaceKey: "SPACEKEY"
+      ancestorId: "123456789"
+      title: Titulo
+
+events:
+  published:
+    CAKE_BURNED:
+      visibility: protected
+      module: cooker
+      description: Evento disparado quando o bolo é queimado
+      attributes:
+        type: object
+        properties:
+          cake:
+            $ref: '#/types/Cake'
+          category:
+            $ref: '#/types/Category'
+            nullable: true
+      entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+            value: "12354"
+
+  consumed:
+    CAKE_PURCHASED:
+      description: Usado para inciar o processo de fazer o bolo
+
+types:
+  CakeShape:
+    description: Enum dos formatos de bolo suportado
+    type: string
+    enum:
+      - squad
+      - circle
+    value: circle
+
+  Cake:
+    description: Representa um bolo
+    type: object
+    properties:
+      shape:
+        $ref: '#/types/CakeShape'
+      weight:
+        type: number
+        nullable: true
+
+  Category:
+    type: object
+    properties:
+      name:
+        type: string
+        value: Bolos
+      parent:
+        $ref: '#/types/Category'
+        nullable: true`
+
+func TestShouldWriteDocumentReadableByTheAsyncAPIDecoder(t *testing.T) {
+	for _, format := range []asyncapi.Format{asyncapi.FormatYAML, asyncapi.FormatJSON} {
+		schemaResolver := schema.NewBasicResolver()
+		if err := yaml.NewDecoder().Decode(strings.NewReader(lifecycleDefinition), schemaResolver); err != nil {
+			t.Fatal(err)
+		}
+
+		var document bytes.Buffer
+		if err := asyncapi.NewDocumentWriter("2.1.0", format).Write(&document, schemaResolver); err != nil {
+			t.Fatal(err)
+		}
+
+		exportedResolver := schema.NewBasicResolver()
+		if err := asyncapiParser.NewDecoder().Decode(&document, exportedResolver); err != nil {
+			t.Fatalf("expected a valid document, received '%s'", err)
+		}
+
+		assertSameDefinitions(t, schemaResolver, exportedResolver)
+	}
+}
+
+func TestShouldWriteEventsAsOperations(t *testing.T) {
+	schemaResolver := schema.NewBasicResolver()
+	if err := yaml.NewDecoder().Decode(strings.NewReader(lifecycleDefinition), schemaResolver); err != nil {
+		t.Fatal(err)
+	}
+
+	var document bytes.Buffer
+	if err := asyncapi.NewDocumentWriter("2.1.0", asyncapi.FormatYAML).Write(&document, schemaResolver); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"asyncapi: 3.0.0",
+		"  version: 2.1.0",
+		"  CAKE_BURNED.send:\n    action: send",
+		"  CAKE_PURCHASED.receive:\n    action: receive",
+		"      x-visibility: protected\n      x-module: cooker",
+		"            - $ref: '#/components/schemas/Category'\n            - type: \"null\"",
+	} {
+		if !strings.Contains(document.String(), expected) {
+			t.Errorf("expected '%s' in the document, received '%s'", expected, document.String())
+		}
+	}
+}
+
+func assertSameDefinitions(t *testing.T, expected, value schema.Resolver) {
+	t.Helper()
+
+	expectedTypes, err := expected.GetTypes()
+	assertNoError(t, err)
+
+	valueTypes, err := value.GetTypes()
+	assertNoError(t, err)
+
+	if len(valueTypes) != len(expectedTypes) {
+		t.Fatalf("expected %d types, received %d", len(expectedTypes), len(valueTypes))
+	}
+
+	for i := range expectedTypes {
+		assertSameType(t, expectedTypes[i], valueTypes[i])
+	}
+
+	expectedEvents, err := expected.GetPublishedEvents()
+	assertNoError(t, err)
+
+	valueEvents, err := value.GetPublishedEvents()
+	assertNoError(t, err)
+
+	if len(valueEvents) != len(expectedEvents) {
+		t.Fatalf("expected %d published events, received %d", len(expectedEvents), len(valueEvents))
+	}
+
+	for i := range expectedEvents {
+		if valueEvents[i].Name() != expectedEvents[i].Name() ||
+			valueEvents[i].Visibility() != expectedEvents[i].Visibility() ||
+			valueEvents[i].Module() != expectedEvents[i].Module() ||
+			valueEvents[i].Description() != expectedEvents[i].Description() {
+			t.Errorf("expected event '%s', received '%s'", expectedEvents[i].Name(), valueEvents[i].Name())
+		}
+
+		assertSameType(t, expectedEvents[i].Attributes(), valueEvents[i].Attributes())
+		assertSameType(t, expectedEvents[i].Entities(), valueEvents[i].Entities())
+	}
+
+	consumedEvents, err := value.GetConsumedEvents()
+	assertNoError(t, err)
+
+	if len(consumedEvents) != 1 || consumedEvents[0].Name() != "CAKE_PURCHASED" {
+		t.Errorf("unexpected consumed events %v", consumedEvents)
+	}
+}
+
+func assertSameType(t *testing.T, expected, value types.TypeDescriber) {
+	t.Helper()
+
+	if value.Path() != expected.Path() ||
+		value.Type() != expected.Type() ||
+		value.Nullable() != expected.Nullable() ||
+		value.Description() != expected.Description() {
+		t.Errorf(
+			"expected '%s' %s nullable '%t', received '%s' %s nullable '%t'",
+			expected.Path(), expected.Type(), expected.Nullable(),
+			value.Path(), value.Type(), value.Nullable(),
+		)
+	}
+
+	if expected, is := expected.(types.ScalarDescriber); is {
+		if value, is := value.(types.ScalarDescriber); !is || value.Value() != expected.Value() {
+			t.Errorf("expected '%s' value '%v', received '%v'", expected.Path(), expected.Value(), value)
+		}
+	}
+
+	expectedObject, is := expected.(types.ObjectDescriber)
+	if !is {
+		return
+	}
+
+	// The recursive references have no properties to compare
+	if _, is := expected.(*types.RecursiveReference); is {
+		return
+	}
+
+	valueObject, is := value.(types.ObjectDescriber)
+	if !is {
+		t.Fatalf("expected object '%s', received '%T'", expected.Path(), value)
+	}
+
+	expectedProperties, valueProperties := expectedObject.Properties(), valueObject.Properties()
+	if len(valueProperties) != len(expectedProperties) {
+		t.Fatalf("expected %d properties in '%s', received %d", len(expectedProperties), expected.Path(), len(valueProperties))
+	}
+
+	for i := range expectedProperties {
+		assertSameType(t, expectedProperties[i], valueProperties[i])
+	}
+}
+
+func assertNoError(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("expected no error, received '%s'", err)
+	}
+}
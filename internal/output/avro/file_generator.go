@@ -0,0 +1,368 @@
+// avro package exports the published events of the lifecycle definitions as Avro schemas
+package avro
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/codegen"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/jsonc"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+)
+
+var (
+	nameRegexp      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	namespaceRegexp = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*)?$`)
+)
+
+// stringLogicalTypes are the Avro logical types, and their underlying types, of the string formats
+var stringLogicalTypes = map[string][2]string{
+	"uuid":      {"string", "uuid"},
+	"date-time": {"long", "timestamp-millis"},
+	"date":      {"int", "date"},
+	"time":      {"int", "time-millis"},
+}
+
+// intFormats are the integer formats that fit in the Avro int, the other formats are encoded as long
+var intFormats = map[string]bool{
+	"int8":   true,
+	"int16":  true,
+	"int32":  true,
+	"uint8":  true,
+	"uint16": true,
+}
+
+/*
+FileGenerator writes an Avro schema of each published event to a directory. The events are exported as records with
+the attributes and entities fields, the objects as records, the enums of strings as enums, the date, time and uuid
+formats as logical types and the nullable definitions as unions with null. The fields of the nullable properties
+default to null, since they can be absent. The records and enums are named by the declared types or, for the nested
+definitions, by their parent and property, like CakeBurnedEventAttributes, and are defined once by schema, being
+referenced by name after it
+*/
+type FileGenerator struct {
+	outDir    string
+	namespace string
+}
+
+func NewFileGenerator(outDir, namespace string) *FileGenerator {
+	return &FileGenerator{
+		outDir:    outDir,
+		namespace: namespace,
+	}
+}
+
+/*
+Generate writes one <event name>.avsc file per published event and returns the written file paths in declaration
+order. The definitions without an Avro equivalent, like enums of numbers and property names that are not valid
+Avro names, are reported as errors
+*/
+func (f *FileGenerator) Generate(schemaResolver schema.Resolver) ([]string, error) {
+	if !namespaceRegexp.MatchString(f.namespace) {
+		return nil, fmt.Errorf("namespace '%s' is not a valid Avro namespace", f.namespace)
+	}
+
+	declaredTypes, err := schemaResolver.GetTypes()
+	if err != nil {
+		return nil, fmt.Errorf("can't get types to export: %w", err)
+	}
+
+	publishedEvents, err := schemaResolver.GetPublishedEvents()
+	if err != nil {
+		return nil, fmt.Errorf("can't get published events to export: %w", err)
+	}
+
+	declared := codegen.NewDeclaredTypes(declaredTypes)
+	declaredByNames := make(map[string]types.TypeDescriber)
+	for i := range declaredTypes {
+		declaredByNames[declaredTypes[i].Name()] = declaredTypes[i]
+	}
+
+	if err := os.MkdirAll(f.outDir, 0755); err != nil {
+		return nil, fmt.Errorf("can't create output directory '%s': %w", f.outDir, err)
+	}
+
+	paths := make([]string, len(publishedEvents))
+
+	for i, event := range publishedEvents {
+		c := &converter{
+			declaredTypes:   declared,
+			declaredByNames: declaredByNames,
+			defined:         make(map[string]types.TypeDescriber),
+			inlining:        make(map[string]bool),
+		}
+
+		eventSchema, err := c.named(codegen.PascalCase(event.Name())+"Event", codegen.EventEnvelope(event))
+		if err != nil {
+			return nil, fmt.Errorf("can't export published event '%s': %w", event.Name(), err)
+		}
+
+		document := eventSchema.(jsonc.MapSlice)
+
+		if len(f.namespace) > 0 {
+			// The namespace follows the name, as in the Avro specification examples
+			document = append(jsonc.MapSlice{document[0], document[1], {Key: "namespace", Value: f.namespace}}, document[2:]...)
+		}
+
+		content, err := marshalSchema(document)
+		if err != nil {
+			return nil, fmt.Errorf("can't encode published event '%s': %w", event.Name(), err)
+		}
+
+		paths[i] = filepath.Join(f.outDir, codegen.FileName(event.Name())+".avsc")
+
+		if err := os.WriteFile(paths[i], content, 0644); err != nil {
+			return nil, fmt.Errorf("can't write published event '%s' to file '%s': %w", event.Name(), paths[i], err)
+		}
+	}
+
+	return paths, nil
+}
+
+// converter converts the definitions of a schema, where each named type is defined once
+type converter struct {
+	declaredTypes   *codegen.DeclaredTypes
+	declaredByNames map[string]types.TypeDescriber
+
+	// defined stores the definitions of the records and enums already defined in the schema by their names
+	defined map[string]types.TypeDescriber
+
+	// inlining stores the declared types being inlined, which can't be recursive since they have no name in Avro
+	inlining map[string]bool
+}
+
+// schema returns the Avro schema of the definition, the name is used by the nested records and enums
+func (c *converter) schema(name string, typeDescriber types.TypeDescriber) (interface{}, error) {
+	referenceName, isReference, err := c.declaredTypes.Reference(typeDescriber)
+	if err != nil {
+		return nil, err
+	}
+
+	if isReference {
+		return c.referenceSchema(referenceName, typeDescriber)
+	}
+
+	var avroSchema interface{}
+
+	switch describer := typeDescriber.(type) {
+	case types.ScalarDescriber:
+		if describer.HasEnum() {
+			avroSchema, err = c.named(name, describer)
+		} else {
+			avroSchema, err = primitiveSchema(describer)
+		}
+	case types.ArrayDescriber:
+		var items interface{}
+
+		if items, err = c.schema(name+"Item", describer.Items()); err == nil {
+			avroSchema = jsonc.MapSlice{{Key: "type", Value: "array"}, {Key: "items", Value: items}}
+		}
+	case types.ObjectDescriber:
+		avroSchema, err = c.named(name, describer)
+	default:
+		err = fmt.Errorf("type '%T' of definition '%s' is not supported", typeDescriber, typeDescriber.Path())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return withNull(avroSchema, typeDescriber.Nullable()), nil
+}
+
+// referenceSchema returns the schema of a reference to a declared type, which is referenced by name when defined
+func (c *converter) referenceSchema(referenceName string, reference types.TypeDescriber) (interface{}, error) {
+	target := c.declaredByNames[referenceName]
+
+	if !isNamed(target) {
+		if c.inlining[referenceName] {
+			return nil, fmt.Errorf(
+				"recursive definition '%s' has no Avro equivalent, it must be recursive through an object",
+				reference.Path(),
+			)
+		}
+
+		c.inlining[referenceName] = true
+		defer delete(c.inlining, referenceName)
+
+		targetSchema, err := c.schema(codegen.PascalCase(referenceName), target)
+		if err != nil {
+			return nil, err
+		}
+
+		return withNull(targetSchema, reference.Nullable()), nil
+	}
+
+	targetSchema, err := c.named(codegen.PascalCase(referenceName), target)
+	if err != nil {
+		return nil, err
+	}
+
+	return withNull(targetSchema, reference.Nullable() || target.Nullable()), nil
+}
+
+// named returns the definition of the record or enum, or its name when already defined in the schema
+func (c *converter) named(name string, typeDescriber types.TypeDescriber) (interface{}, error) {
+	if definition, isDefined := c.defined[name]; isDefined {
+		if definition != typeDescriber {
+			return nil, fmt.Errorf(
+				"name '%s' of definition '%s' is already used by definition '%s'",
+				name,
+				typeDescriber.Path(),
+				definition.Path(),
+			)
+		}
+
+		return name, nil
+	}
+
+	// The records are defined before their fields, allowing the recursive records to reference themselves
+	c.defined[name] = typeDescriber
+
+	switch describer := typeDescriber.(type) {
+	case types.ScalarDescriber:
+		return c.enum(name, describer)
+	case types.ObjectDescriber:
+		return c.record(name, describer)
+	}
+
+	return nil, fmt.Errorf("type '%T' of definition '%s' is not supported", typeDescriber, typeDescriber.Path())
+}
+
+func (c *converter) record(name string, object types.ObjectDescriber) (jsonc.MapSlice, error) {
+	fields := []jsonc.MapSlice{}
+
+	for _, property := range object.Properties() {
+		if !nameRegexp.MatchString(property.Name()) {
+			return nil, fmt.Errorf("property name of definition '%s' is not a valid Avro name", property.Path())
+		}
+
+		fieldSchema, err := c.schema(name+codegen.PascalCase(property.Name()), property)
+		if err != nil {
+			return nil, err
+		}
+
+		field := jsonc.MapSlice{{Key: "name", Value: property.Name()}}
+
+		if description := c.declaredTypes.Description(property); len(description) > 0 {
+			field = append(field, jsonc.MapItem{Key: "doc", Value: description})
+		}
+
+		field = append(field, jsonc.MapItem{Key: "type", Value: fieldSchema})
+
+		// The nullable properties can be absent, the default is the first type of the union with null
+		if property.Nullable() {
+			field = append(field, jsonc.MapItem{Key: "default", Value: nil})
+		}
+
+		fields = append(fields, field)
+	}
+
+	record := jsonc.MapSlice{{Key: "type", Value: "record"}, {Key: "name", Value: name}}
+
+	if description := c.declaredTypes.Description(object); len(description) > 0 {
+		record = append(record, jsonc.MapItem{Key: "doc", Value: description})
+	}
+
+	return append(record, jsonc.MapItem{Key: "fields", Value: fields}), nil
+}
+
+func (c *converter) enum(name string, scalarType types.ScalarDescriber) (jsonc.MapSlice, error) {
+	symbols := []string{}
+
+	for _, value := range scalarType.Enum() {
+		// The null value is exported by the nullable union
+		if value == nil {
+			continue
+		}
+
+		symbol, isString := value.(string)
+		if !isString || scalarType.Type() != types.ScalarStringType {
+			return nil, fmt.Errorf("enum of type '%s' of definition '%s' has no Avro equivalent", scalarType.Type(), scalarType.Path())
+		}
+
+		if !nameRegexp.MatchString(symbol) {
+			return nil, fmt.Errorf("enum value '%s' of definition '%s' is not a valid Avro symbol", symbol, scalarType.Path())
+		}
+
+		symbols = append(symbols, symbol)
+	}
+
+	enum := jsonc.MapSlice{{Key: "type", Value: "enum"}, {Key: "name", Value: name}}
+
+	if description := c.declaredTypes.Description(scalarType); len(description) > 0 {
+		enum = append(enum, jsonc.MapItem{Key: "doc", Value: description})
+	}
+
+	return append(enum, jsonc.MapItem{Key: "symbols", Value: symbols}), nil
+}
+
+func primitiveSchema(scalarType types.ScalarDescriber) (interface{}, error) {
+	switch scalarType.Type() {
+	case types.ScalarStringType:
+		if logicalType, exists := stringLogicalTypes[scalarType.Format()]; exists {
+			return jsonc.MapSlice{{Key: "type", Value: logicalType[0]}, {Key: "logicalType", Value: logicalType[1]}}, nil
+		}
+
+		return "string", nil
+	case types.ScalarIntegerType:
+		switch {
+		case intFormats[scalarType.Format()]:
+			return "int", nil
+		case scalarType.Format() == "uint64":
+			return nil, fmt.Errorf("format 'uint64' of definition '%s' has no Avro equivalent", scalarType.Path())
+		}
+
+		return "long", nil
+	case types.ScalarNumberType:
+		if scalarType.Format() == "float" {
+			return "float", nil
+		}
+
+		return "double", nil
+	case types.ScalarBooleanType:
+		return "boolean", nil
+	}
+
+	return nil, fmt.Errorf("type '%s' of definition '%s' is not supported", scalarType.Type(), scalarType.Path())
+}
+
+// isNamed reports whether the declared type is exported as a named type of Avro, a record or an enum
+func isNamed(typeDescriber types.TypeDescriber) bool {
+	switch describer := typeDescriber.(type) {
+	case types.ScalarDescriber:
+		return describer.HasEnum()
+	case types.ObjectDescriber:
+		return true
+	}
+
+	return false
+}
+
+// withNull returns the union of null with the schema for the nullable definitions, the unions can't be nested
+func withNull(avroSchema interface{}, nullable bool) interface{} {
+	if _, isUnion := avroSchema.([]interface{}); !nullable || isUnion {
+		return avroSchema
+	}
+
+	return []interface{}{"null", avroSchema}
+}
+
+func marshalSchema(document jsonc.MapSlice) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,256 @@
+package avro_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/avro"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+)
+
+func TestShouldGenerateAvroSchemaFiles(t *testing.T) {
+	schemaResolver := decode(t, `
+version: "1.0"
+name: super-cool-service
+
+events:
+  published:
+    cake/burned:
+      visibility: public
+      description: Evento disparado quando o bolo é queimado
+      attributes:
+        type: object
+        properties:
+          cake:
+            $ref: '#/types/Cake'
+          previous:
+            $ref: '#/types/Cake'
+            nullable: true
+          burnedAt:
+            type: string
+            format: date-time
+            value: "2022-10-20T10:00:00Z"
+          burnedOn:
+            type: string
+            format: date
+            value: "2022-10-20"
+          burnedTime:
+            type: string
+            format: time
+            value: "10:00:00"
+      entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+            format: uuid
+            value: 41af6672-5b3a-4d5c-9be1-7c93dc1614e1
+
+types:
+  CakeShape:
+    description: Enum dos formatos de bolo suportado
+    type: string
+    nullable: true
+    enum:
+      - squad
+      - circle
+    value: circle
+
+  Cake:
+    type: object
+    properties:
+      shape:
+        $ref: '#/types/CakeShape'
+      weight:
+        type: integer
+        format: uint8
+        value: 5
+      layers:
+        type: array
+        description: Camadas do bolo
+        items:
+          $ref: '#/types/Cake'`)
+
+	outDir := t.TempDir()
+
+	paths, err := avro.NewFileGenerator(outDir, "com.example.cakes").Generate(schemaResolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPaths := []string{filepath.Join(outDir, "cake_burned.avsc")}
+	if !reflect.DeepEqual(paths, expectedPaths) {
+		t.Fatalf("expected '%v' paths, received '%v'", expectedPaths, paths)
+	}
+
+	content, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{
+  "type": "record",
+  "name": "CakeBurnedEvent",
+  "namespace": "com.example.cakes",
+  "doc": "Evento disparado quando o bolo é queimado",
+  "fields": [
+    {
+      "name": "attributes",
+      "type": {
+        "type": "record",
+        "name": "CakeBurnedEventAttributes",
+        "fields": [
+          {
+            "name": "cake",
+            "type": {
+              "type": "record",
+              "name": "Cake",
+              "fields": [
+                {
+                  "name": "shape",
+                  "type": [
+                    "null",
+                    {
+                      "type": "enum",
+                      "name": "CakeShape",
+                      "doc": "Enum dos formatos de bolo suportado",
+                      "symbols": [
+                        "squad",
+                        "circle"
+                      ]
+                    }
+                  ]
+                },
+                {
+                  "name": "weight",
+                  "type": "int"
+                },
+                {
+                  "name": "layers",
+                  "doc": "Camadas do bolo",
+                  "type": {
+                    "type": "array",
+                    "items": "Cake"
+                  }
+                }
+              ]
+            }
+          },
+          {
+            "name": "previous",
+            "type": [
+              "null",
+              "Cake"
+            ],
+            "default": null
+          },
+          {
+            "name": "burnedAt",
+            "type": {
+              "type": "long",
+              "logicalType": "timestamp-millis"
+            }
+          },
+          {
+            "name": "burnedOn",
+            "type": {
+              "type": "int",
+              "logicalType": "date"
+            }
+          },
+          {
+            "name": "burnedTime",
+            "type": {
+              "type": "int",
+              "logicalType": "time-millis"
+            }
+          }
+        ]
+      }
+    },
+    {
+      "name": "entities",
+      "type": {
+        "type": "record",
+        "name": "CakeBurnedEventEntities",
+        "fields": [
+          {
+            "name": "cakeId",
+            "type": {
+              "type": "string",
+              "logicalType": "uuid"
+            }
+          }
+        ]
+      }
+    }
+  ]
+}
+`
+
+	if string(content) != expected {
+		t.Errorf("expected '%s', received '%s'", expected, content)
+	}
+}
+
+func TestShouldReportDefinitionsWithoutAvroEquivalent(t *testing.T) {
+	for definition, expectedErr := range map[string]string{
+		`
+          burned-at:
+            type: string
+            value: "2022-10-20T10:00:00Z"`: "property name of definition '#/events/published/CAKE_BURNED/attributes/properties/burned-at' is not a valid Avro name",
+		`
+          layers:
+            type: integer
+            enum: [1, 2]
+            value: 1`: "enum of type 'integer' of definition '#/events/published/CAKE_BURNED/attributes/properties/layers' has no Avro equivalent",
+		`
+          shape:
+            type: string
+            enum: [square-ish]
+            value: square-ish`: "enum value 'square-ish' of definition '#/events/published/CAKE_BURNED/attributes/properties/shape' is not a valid Avro symbol",
+		`
+          weight:
+            type: integer
+            format: uint64
+            value: 1`: "format 'uint64' of definition '#/events/published/CAKE_BURNED/attributes/properties/weight' has no Avro equivalent",
+	} {
+		schemaResolver := decode(t, `
+version: "1.0"
+name: super-cool-service
+
+events:
+  published:
+    CAKE_BURNED:
+      visibility: public
+      attributes:
+        type: object
+        properties:`+definition+`
+      entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+            value: "12354"`)
+
+		_, err := avro.NewFileGenerator(t.TempDir(), "").Generate(schemaResolver)
+		if err == nil || !strings.HasSuffix(err.Error(), expectedErr) {
+			t.Errorf("expected '%s' error, received '%v'", expectedErr, err)
+		}
+	}
+}
+
+func decode(t *testing.T, definition string) schema.Resolver {
+	t.Helper()
+
+	schemaResolver := schema.NewBasicResolver()
+	if err := yaml.NewDecoder().Decode(strings.NewReader(definition), schemaResolver); err != nil {
+		t.Fatal(err)
+	}
+
+	return schemaResolver
+}
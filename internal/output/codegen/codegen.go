@@ -0,0 +1,194 @@
+// codegen package has the shared helpers of the code generators of the lifecycle definitions
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+)
+
+// GeneratedHeader marks the generated files, following the Go convention recognized by linters and code review tools
+const GeneratedHeader = "Code generated by lifecycledoc. DO NOT EDIT."
+
+/*
+DeclaredTypes identifies the references to declared types, which are generated once and referenced by name.
+The references to undeclared types, like nested definitions, must be inlined by the generators
+*/
+type DeclaredTypes struct {
+	// names stores the names of the declared types by their underlying definition, which is shared by the references
+	names map[types.TypeDescriber]string
+}
+
+func NewDeclaredTypes(declaredTypes []types.TypeDescriber) *DeclaredTypes {
+	names := make(map[types.TypeDescriber]string)
+
+	for i := range declaredTypes {
+		// The declared references are references to other types, they are not the name of the referenced type
+		if _, is := declaredTypes[i].(types.ReferenceDescriber); !is {
+			names[declaredTypes[i]] = declaredTypes[i].Name()
+		}
+	}
+
+	return &DeclaredTypes{
+		names: names,
+	}
+}
+
+// Reference returns the name of the declared type referenced by the definition, if the definition is a reference to it
+func (d *DeclaredTypes) Reference(typeDescriber types.TypeDescriber) (string, bool, error) {
+	target := referenceTarget(typeDescriber)
+	if target == nil {
+		return "", false, nil
+	}
+
+	name, exists := d.names[target]
+
+	if _, isRecursive := typeDescriber.(*types.RecursiveReference); isRecursive && !exists {
+		return "", false, fmt.Errorf(
+			"recursive definition '%s' must reference a declared type to be generated",
+			typeDescriber.Path(),
+		)
+	}
+
+	return name, exists, nil
+}
+
+/*
+Description returns the description of the definition to be documented. The references to declared types only have
+their own description, since the description of the declared type is documented with it
+*/
+func (d *DeclaredTypes) Description(typeDescriber types.TypeDescriber) string {
+	if target := referenceTarget(typeDescriber); target != nil && target.Description() == typeDescriber.Description() {
+		if _, exists := d.names[target]; exists {
+			return ""
+		}
+	}
+
+	return typeDescriber.Description()
+}
+
+// referenceTarget returns the referenced definition, nil for definitions that are not references
+func referenceTarget(typeDescriber types.TypeDescriber) types.TypeDescriber {
+	switch typeDescriber := typeDescriber.(type) {
+	case *types.RecursiveReference:
+		return typeDescriber.Target()
+	case *types.ScalarReference:
+		return typeDescriber.Scalar
+	case *types.ArrayReference:
+		return typeDescriber.Array
+	case *types.ObjectReference:
+		return typeDescriber.Object
+	}
+
+	return nil
+}
+
+/*
+EventEnvelope returns the message of the published event as an object with the attributes and entities properties,
+allowing the generators to generate the events like the declared objects
+*/
+func EventEnvelope(event *types.PublishedEvent) types.TypeDescriber {
+	// The properties are already validated by the resolver
+	envelope, _ := types.NewObject(
+		event.Name(),
+		"#/events/published/"+event.Name(),
+		event.Description(),
+		false,
+		[]types.TypeDescriber{event.Attributes(), event.Entities()},
+	)
+
+	return envelope
+}
+
+// FileName replaces the characters of the name that can't be used in file names, like the slashes of cake/burned
+func FileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+
+		if r < ' ' {
+			return '_'
+		}
+
+		return r
+	}, name)
+}
+
+// PascalCase converts the name to an identifier in PascalCase, like CAKE_BURNED or cake/burned to CakeBurned
+func PascalCase(name string) string {
+	var identifier strings.Builder
+
+	for _, word := range words(name) {
+		runes := []rune(word)
+		identifier.WriteRune(unicode.ToUpper(runes[0]))
+
+		// The words in upper case, like in CAKE_BURNED, are not acronyms
+		if strings.ToUpper(word) == word {
+			identifier.WriteString(strings.ToLower(string(runes[1:])))
+		} else {
+			identifier.WriteString(string(runes[1:]))
+		}
+	}
+
+	return prefixDigit(identifier.String())
+}
+
+// CamelCase converts the name to an identifier in camelCase, like CAKE_BURNED or cake/burned to cakeBurned
+func CamelCase(name string) string {
+	identifier := []rune(PascalCase(name))
+
+	for i := range identifier {
+		// The initial acronyms are converted entirely, like URLPath to urlPath
+		if i > 0 && i+1 < len(identifier) && unicode.IsLower(identifier[i+1]) {
+			break
+		}
+
+		identifier[i] = unicode.ToLower(identifier[i])
+	}
+
+	return string(identifier)
+}
+
+// SnakeCase converts the name to an identifier in snake_case, like cakeId or CAKE_ID to cake_id
+func SnakeCase(name string) string {
+	var (
+		identifier strings.Builder
+		runes      = []rune(PascalCase(name))
+	)
+
+	for i, r := range runes {
+		// The words start on the upper case letters, except inside acronyms like in URLPath
+		if i > 0 && unicode.IsUpper(r) &&
+			(!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			identifier.WriteRune('_')
+		}
+
+		identifier.WriteRune(unicode.ToLower(r))
+	}
+
+	return identifier.String()
+}
+
+// words splits the name on the characters that can't be used in identifiers
+func words(name string) []string {
+	return strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// prefixDigit prefixes the identifiers starting with digits, which are invalid in most languages
+func prefixDigit(identifier string) string {
+	if len(identifier) < 1 {
+		return "_"
+	}
+
+	if unicode.IsDigit([]rune(identifier)[0]) {
+		return "_" + identifier
+	}
+
+	return identifier
+}
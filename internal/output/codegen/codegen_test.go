@@ -0,0 +1,31 @@
+package codegen_test
+
+import (
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/codegen"
+)
+
+func TestShouldConvertNamesToIdentifiers(t *testing.T) {
+	for name, expected := range map[string][3]string{
+		"CAKE_BURNED":  {"CakeBurned", "cakeBurned", "cake_burned"},
+		"cake/burned":  {"CakeBurned", "cakeBurned", "cake_burned"},
+		"cake.v2-sold": {"CakeV2Sold", "cakeV2Sold", "cake_v2_sold"},
+		"CakeShape":    {"CakeShape", "cakeShape", "cake_shape"},
+		"URLPath":      {"URLPath", "urlPath", "url_path"},
+		"ID":           {"Id", "id", "id"},
+		"3d_cake":      {"_3dCake", "_3dCake", "_3d_cake"},
+	} {
+		if pascalCase := codegen.PascalCase(name); pascalCase != expected[0] {
+			t.Errorf("expected '%s' in PascalCase, received '%s'", expected[0], pascalCase)
+		}
+
+		if camelCase := codegen.CamelCase(name); camelCase != expected[1] {
+			t.Errorf("expected '%s' in camelCase, received '%s'", expected[1], camelCase)
+		}
+
+		if snakeCase := codegen.SnakeCase(name); snakeCase != expected[2] {
+			t.Errorf("expected '%s' in snake_case, received '%s'", expected[2], snakeCase)
+		}
+	}
+}
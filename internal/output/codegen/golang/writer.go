@@ -0,0 +1,431 @@
+// golang package generates Go types of the lifecycle definitions
+package golang
+
+import (
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/codegen"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+)
+
+const timeType = "time.Time"
+
+// integerFormats are the Go types of the integer formats
+var integerFormats = map[string]string{
+	"int8":   "int8",
+	"int16":  "int16",
+	"int32":  "int32",
+	"int64":  "int64",
+	"uint8":  "uint8",
+	"uint16": "uint16",
+	"uint32": "uint32",
+	"uint64": "uint64",
+}
+
+// initialisms are the initialisms written in upper case in Go identifiers, like ID in CakeID
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "GUID": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "QPS": true, "RAM": true,
+	"RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true,
+	"UDP": true, "UI": true, "UID": true, "UUID": true, "URI": true, "URL": true, "UTF8": true, "VM": true,
+	"XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+/*
+Writer writes the Go types of the declared types and of the published events. The objects are declared as structs
+with JSON tags, the enums of strings and integers as named types with a constant by value and the nullable definitions
+as pointers. The nullable properties are omitted from the JSON when nil. The published events are declared as structs named by the event with the "Event" suffix, like
+CakeBurnedEvent. The nested objects and enums are declared as types named by their parent and property, like
+CakeBurnedEventAttributes. The identifiers follow the Go initialisms, like CakeID, and the identifiers declared twice,
+like the fields of the cake_id and cakeId properties, are reported as errors
+*/
+type Writer struct {
+	packageName string
+}
+
+func NewWriter(packageName string) *Writer {
+	return &Writer{
+		packageName: packageName,
+	}
+}
+
+func (w *Writer) Write(out io.Writer, schemaResolver schema.Resolver) error {
+	declaredTypes, err := schemaResolver.GetTypes()
+	if err != nil {
+		return fmt.Errorf("can't get types to generate: %w", err)
+	}
+
+	publishedEvents, err := schemaResolver.GetPublishedEvents()
+	if err != nil {
+		return fmt.Errorf("can't get published events to generate: %w", err)
+	}
+
+	g := &generator{
+		declaredTypes:   codegen.NewDeclaredTypes(declaredTypes),
+		declaredByNames: make(map[string]types.TypeDescriber),
+		identifiers:     make(map[string]string),
+	}
+
+	for i := range declaredTypes {
+		g.declaredByNames[declaredTypes[i].Name()] = declaredTypes[i]
+	}
+
+	for _, typeDescriber := range declaredTypes {
+		if err := g.declare(identifier(typeDescriber.Name()), typeDescriber); err != nil {
+			return err
+		}
+	}
+
+	for _, event := range publishedEvents {
+		if err := g.declare(identifier(event.Name())+"Event", codegen.EventEnvelope(event)); err != nil {
+			return fmt.Errorf("can't generate published event '%s': %w", event.Name(), err)
+		}
+	}
+
+	var code strings.Builder
+	code.WriteString("// " + codegen.GeneratedHeader + "\n\n")
+	code.WriteString("package " + w.packageName + "\n")
+
+	if g.usesTime {
+		code.WriteString("\nimport \"time\"\n")
+	}
+
+	for i := range g.declarations {
+		code.WriteString("\n" + g.declarations[i])
+	}
+
+	formatted, err := format.Source([]byte(code.String()))
+	if err != nil {
+		return fmt.Errorf("can't format go code of package '%s': %w", w.packageName, err)
+	}
+
+	if _, err := out.Write(formatted); err != nil {
+		return fmt.Errorf("can't write go code: %w", err)
+	}
+
+	return nil
+}
+
+type generator struct {
+	declaredTypes   *codegen.DeclaredTypes
+	declaredByNames map[string]types.TypeDescriber
+	declarations    []string
+	usesTime        bool
+
+	// identifiers stores the package level identifiers already declared, by the definitions declaring them
+	identifiers map[string]string
+}
+
+// declare declares the definition as a named type, the nested objects and enums are declared after it
+func (g *generator) declare(name string, typeDescriber types.TypeDescriber) error {
+	if err := g.declareIdentifier(name, fmt.Sprintf("definition '%s'", typeDescriber.Path())); err != nil {
+		return err
+	}
+
+	index := len(g.declarations)
+	g.declarations = append(g.declarations, "")
+
+	declaration := docComment(g.declaredTypes.Description(typeDescriber), "")
+
+	referenceName, isReference, err := g.declaredTypes.Reference(typeDescriber)
+	if err != nil {
+		return err
+	}
+
+	if isReference {
+		declaration += fmt.Sprintf("type %s = %s\n", name, identifier(referenceName))
+		g.declarations[index] = declaration
+
+		return nil
+	}
+
+	// The references to undeclared definitions are declared like the referenced definitions
+	switch describer := typeDescriber.(type) {
+	case types.ScalarDescriber:
+		if isEnum(describer) {
+			underlyingType, err := g.scalarType(describer)
+			if err != nil {
+				return err
+			}
+
+			constants, err := g.enumConstants(name, describer)
+			if err != nil {
+				return err
+			}
+
+			declaration += fmt.Sprintf("type %s %s\n", name, underlyingType) + constants
+			g.declarations[index] = declaration
+
+			return nil
+		}
+	case types.ObjectDescriber:
+		fields, err := g.structFields(name, describer)
+		if err != nil {
+			return err
+		}
+
+		declaration += fmt.Sprintf("type %s struct {\n%s}\n", name, fields)
+		g.declarations[index] = declaration
+
+		return nil
+	}
+
+	underlyingType, err := g.underlyingType(name, typeDescriber)
+	if err != nil {
+		return err
+	}
+
+	// The types of other packages are aliased to keep their methods, like the JSON encoding of time.Time
+	if strings.Contains(underlyingType, ".") {
+		declaration += fmt.Sprintf("type %s = %s\n", name, underlyingType)
+	} else {
+		declaration += fmt.Sprintf("type %s %s\n", name, underlyingType)
+	}
+
+	g.declarations[index] = declaration
+
+	return nil
+}
+
+func (g *generator) structFields(name string, object types.ObjectDescriber) (string, error) {
+	var (
+		fields strings.Builder
+		names  = make(map[string]string)
+	)
+
+	for _, property := range object.Properties() {
+		fieldName := identifier(property.Name())
+
+		if propertyName, exists := names[fieldName]; exists {
+			return "", fmt.Errorf(
+				"field name '%s' of definition '%s' is already used by property '%s'",
+				fieldName,
+				property.Path(),
+				propertyName,
+			)
+		}
+
+		names[fieldName] = property.Name()
+
+		fieldType, err := g.fieldType(name+fieldName, property)
+		if err != nil {
+			return "", err
+		}
+
+		tag := property.Name()
+		// The nullable properties can be absent, the slices are not omitted since the empty arrays would be omitted too
+		if property.Nullable() && strings.HasPrefix(fieldType, "*") {
+			tag += ",omitempty"
+		}
+
+		fields.WriteString(docComment(g.declaredTypes.Description(property), "\t"))
+		fields.WriteString(fmt.Sprintf("\t%s %s `json:%s`\n", fieldName, fieldType, strconv.Quote(tag)))
+	}
+
+	return fields.String(), nil
+}
+
+// fieldType returns the type of the definition, declaring the nested objects and enums with the name
+func (g *generator) fieldType(name string, typeDescriber types.TypeDescriber) (string, error) {
+	referenceName, isReference, err := g.declaredTypes.Reference(typeDescriber)
+	if err != nil {
+		return "", err
+	}
+
+	if isReference {
+		target := g.declaredByNames[referenceName]
+		_, isArray := target.(types.ArrayDescriber)
+
+		return withPointer(identifier(referenceName), !isArray && (typeDescriber.Nullable() || target.Nullable())), nil
+	}
+
+	switch describer := typeDescriber.(type) {
+	case types.ScalarDescriber:
+		if isEnum(describer) {
+			if err := g.declare(name, describer); err != nil {
+				return "", err
+			}
+
+			return withPointer(name, describer.Nullable()), nil
+		}
+	case types.ObjectDescriber:
+		if err := g.declare(name, describer); err != nil {
+			return "", err
+		}
+
+		return withPointer(name, describer.Nullable()), nil
+	}
+
+	underlyingType, err := g.underlyingType(name, typeDescriber)
+	if err != nil {
+		return "", err
+	}
+
+	// The nil slices are already encoded as null
+	if _, isArray := typeDescriber.(types.ArrayDescriber); isArray {
+		return underlyingType, nil
+	}
+
+	return withPointer(underlyingType, typeDescriber.Nullable()), nil
+}
+
+// underlyingType returns the type of the arrays and of the scalars that are not enums
+func (g *generator) underlyingType(name string, typeDescriber types.TypeDescriber) (string, error) {
+	switch describer := typeDescriber.(type) {
+	case types.ScalarDescriber:
+		return g.scalarType(describer)
+	case types.ArrayDescriber:
+		items, err := g.fieldType(name+"Item", describer.Items())
+		if err != nil {
+			return "", err
+		}
+
+		return "[]" + items, nil
+	}
+
+	return "", fmt.Errorf("type '%T' of definition '%s' is not supported", typeDescriber, typeDescriber.Path())
+}
+
+func (g *generator) scalarType(scalarType types.ScalarDescriber) (string, error) {
+	switch scalarType.Type() {
+	case types.ScalarStringType:
+		if scalarType.Format() == "date-time" {
+			g.usesTime = true
+			return timeType, nil
+		}
+
+		return "string", nil
+	case types.ScalarIntegerType:
+		if integerType, exists := integerFormats[scalarType.Format()]; exists {
+			return integerType, nil
+		}
+
+		return "int64", nil
+	case types.ScalarNumberType:
+		if scalarType.Format() == "float" {
+			return "float32", nil
+		}
+
+		return "float64", nil
+	case types.ScalarBooleanType:
+		return "bool", nil
+	}
+
+	return "", fmt.Errorf("type '%s' of definition '%s' is not supported", scalarType.Type(), scalarType.Path())
+}
+
+// declareIdentifier reserves the package level identifier, which can't be declared twice
+func (g *generator) declareIdentifier(name, declaredBy string) error {
+	if previous, exists := g.identifiers[name]; exists {
+		return fmt.Errorf("identifier '%s' of %s is already declared by %s", name, declaredBy, previous)
+	}
+
+	g.identifiers[name] = declaredBy
+
+	return nil
+}
+
+// enumConstants returns the constants of the enum values, named by the enum and the value, like CakeShapeCircle
+func (g *generator) enumConstants(name string, scalarType types.ScalarDescriber) (string, error) {
+	var constants strings.Builder
+	constants.WriteString("\nconst (\n")
+
+	for _, value := range scalarType.Enum() {
+		// The null value is declared by the nullable type
+		if value == nil {
+			continue
+		}
+
+		var constantName, literal string
+
+		if stringValue, isString := value.(string); isString {
+			constantName, literal = name+valueIdentifier(stringValue), strconv.Quote(stringValue)
+		} else {
+			literal = fmt.Sprint(value)
+			constantName = name + strings.Replace(literal, "-", "Minus", 1)
+		}
+
+		declaredBy := fmt.Sprintf("enum value '%v' of definition '%s'", value, scalarType.Path())
+		if err := g.declareIdentifier(constantName, declaredBy); err != nil {
+			return "", err
+		}
+
+		constants.WriteString(fmt.Sprintf("\t%s %s = %s\n", constantName, name, literal))
+	}
+
+	constants.WriteString(")\n")
+
+	return constants.String(), nil
+}
+
+// isEnum reports whether the definition is an enum of strings or integers, which are declared with typed constants
+func isEnum(scalarType types.ScalarDescriber) bool {
+	if !scalarType.HasEnum() {
+		return false
+	}
+
+	return scalarType.Type() == types.ScalarStringType || scalarType.Type() == types.ScalarIntegerType
+}
+
+// identifier converts the name to an exported Go identifier, with the initialisms in upper case like CakeID
+func identifier(name string) string {
+	var (
+		goIdentifier strings.Builder
+		runes        = []rune(codegen.PascalCase(name))
+		start        = 0
+	)
+
+	for i := 1; i <= len(runes); i++ {
+		if i < len(runes) && (!unicode.IsUpper(runes[i]) || unicode.IsUpper(runes[i-1])) {
+			continue
+		}
+
+		word := string(runes[start:i])
+		if initialisms[strings.ToUpper(word)] {
+			word = strings.ToUpper(word)
+		}
+
+		goIdentifier.WriteString(word)
+		start = i
+	}
+
+	return goIdentifier.String()
+}
+
+// valueIdentifier returns the identifier of the enum value, the empty string has no identifier by itself
+func valueIdentifier(value string) string {
+	if len(value) < 1 {
+		return "Empty"
+	}
+
+	return identifier(value)
+}
+
+func docComment(description, indentation string) string {
+	description = strings.TrimSpace(description)
+	if len(description) < 1 {
+		return ""
+	}
+
+	var comment strings.Builder
+
+	for _, line := range strings.Split(description, "\n") {
+		comment.WriteString(strings.TrimRight(indentation+"// "+line, " ") + "\n")
+	}
+
+	return comment.String()
+}
+
+func withPointer(goType string, nullable bool) string {
+	if nullable {
+		return "*" + goType
+	}
+
+	return goType
+}
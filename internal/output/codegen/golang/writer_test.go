@@ -0,0 +1,272 @@
+package golang_test
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/codegen/golang"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+)
+
+func TestShouldWriteGoTypes(t *testing.T) {
+	schemaResolver := schema.NewBasicResolver()
+	err := yaml.NewDecoder().Decode(strings.NewReader(`
+version: "1.0"
+name: super-cool-service
+
+events:
+  published:
+    CAKE_BURNED:
+      visibility: public
+      description: Evento disparado quando o bolo é queimado
+      attributes:
+        type: object
+        properties:
+          cake:
+            $ref: '#/types/Cake'
+          burned-at:
+            type: string
+            format: date-time
+            value: "2022-10-20T10:00:00Z"
+          intensity:
+            type: string
+            nullable: true
+            enum: [low, HIGH, ""]
+            value: low
+      entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+            format: uuid
+            value: 41af6672-5b3a-4d5c-9be1-7c93dc1614e1
+
+types:
+  CakeShape:
+    description: Enum dos formatos de bolo suportado
+    type: string
+    nullable: true
+    enum:
+      - squad
+      - circle
+    value: circle
+
+  Cake:
+    description: |-
+      Representa um bolo
+      com camadas
+    type: object
+    properties:
+      shape:
+        $ref: '#/types/CakeShape'
+      weight:
+        type: integer
+        format: uint8
+        nullable: true
+        value: 5
+      layers:
+        type: array
+        description: Camadas do bolo
+        items:
+          type: integer
+          enum: [-1, 2]
+          value: 2
+      categories:
+        type: array
+        items:
+          $ref: '#/types/Category'
+
+  Category:
+    type: object
+    properties:
+      parent:
+        $ref: '#/types/Category'
+        nullable: true`), schemaResolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var code strings.Builder
+	if err := golang.NewWriter("events").Write(&code, schemaResolver); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "// Code generated by lifecycledoc. DO NOT EDIT.\n" + `
+package events
+
+import "time"
+
+// Enum dos formatos de bolo suportado
+type CakeShape string
+
+const (
+	CakeShapeSquad  CakeShape = "squad"
+	CakeShapeCircle CakeShape = "circle"
+)
+
+// Representa um bolo
+// com camadas
+type Cake struct {
+	Shape  *CakeShape ` + "`json:\"shape\"`" + `
+	Weight *uint8     ` + "`json:\"weight,omitempty\"`" + `
+	// Camadas do bolo
+	Layers     []CakeLayersItem ` + "`json:\"layers\"`" + `
+	Categories []Category       ` + "`json:\"categories\"`" + `
+}
+
+type CakeLayersItem int64
+
+const (
+	CakeLayersItemMinus1 CakeLayersItem = -1
+	CakeLayersItem2      CakeLayersItem = 2
+)
+
+type Category struct {
+	Parent *Category ` + "`json:\"parent,omitempty\"`" + `
+}
+
+// Evento disparado quando o bolo é queimado
+type CakeBurnedEvent struct {
+	Attributes CakeBurnedEventAttributes ` + "`json:\"attributes\"`" + `
+	Entities   CakeBurnedEventEntities   ` + "`json:\"entities\"`" + `
+}
+
+type CakeBurnedEventAttributes struct {
+	Cake      Cake                                ` + "`json:\"cake\"`" + `
+	BurnedAt  time.Time                           ` + "`json:\"burned-at\"`" + `
+	Intensity *CakeBurnedEventAttributesIntensity ` + "`json:\"intensity,omitempty\"`" + `
+}
+
+type CakeBurnedEventAttributesIntensity string
+
+const (
+	CakeBurnedEventAttributesIntensityLow   CakeBurnedEventAttributesIntensity = "low"
+	CakeBurnedEventAttributesIntensityHigh  CakeBurnedEventAttributesIntensity = "HIGH"
+	CakeBurnedEventAttributesIntensityEmpty CakeBurnedEventAttributesIntensity = ""
+)
+
+type CakeBurnedEventEntities struct {
+	CakeID string ` + "`json:\"cakeId\"`" + `
+}
+`
+
+	if code.String() != expected {
+		t.Errorf("expected '%s', received '%s'", expected, code.String())
+	}
+
+	typeCheck(t, code.String())
+}
+
+func TestShouldReportDuplicatedIdentifiers(t *testing.T) {
+	for definition, expectedErr := range map[string]string{
+		`
+          cake_id:
+            type: string
+            value: "12354"
+          cakeId:
+            type: string
+            value: "12354"`: "field name 'CakeID' of definition '#/events/published/CAKE_BURNED/attributes/properties/cakeId' is already used by property 'cake_id'",
+		`
+          status:
+            type: string
+            enum: [a-b, a_b]
+            value: a-b`: "identifier 'CakeBurnedEventAttributesStatusAB' of enum value 'a_b' of definition '#/events/published/CAKE_BURNED/attributes/properties/status' is already declared by enum value 'a-b' of definition '#/events/published/CAKE_BURNED/attributes/properties/status'",
+	} {
+		schemaResolver := decode(t, `
+version: "1.0"
+name: super-cool-service
+
+events:
+  published:
+    CAKE_BURNED:
+      visibility: public
+      attributes:
+        type: object
+        properties:`+definition+`
+      entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+            value: "12354"`)
+
+		err := golang.NewWriter("events").Write(&strings.Builder{}, schemaResolver)
+		if err == nil || !strings.HasSuffix(err.Error(), expectedErr) {
+			t.Errorf("expected '%s' error, received '%v'", expectedErr, err)
+		}
+	}
+}
+
+func TestShouldReportDuplicatedTypeNames(t *testing.T) {
+	schemaResolver := decode(t, `
+version: "1.0"
+name: super-cool-service
+
+events:
+  published:
+    CAKE_BURNED:
+      visibility: public
+      attributes:
+        type: object
+        properties:
+          status:
+            type: string
+            enum: [active]
+            value: active
+          statusActive:
+            type: boolean
+            enum: [true]
+            value: true
+      entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+            value: "12354"
+
+types:
+  CakeBurnedEventAttributesStatusActive:
+    type: string
+    value: x`)
+
+	expectedErr := "identifier 'CakeBurnedEventAttributesStatusActive' of enum value 'active' of definition '#/events/published/CAKE_BURNED/attributes/properties/status' is already declared by definition '#/types/CakeBurnedEventAttributesStatusActive'"
+
+	err := golang.NewWriter("events").Write(&strings.Builder{}, schemaResolver)
+	if err == nil || !strings.HasSuffix(err.Error(), expectedErr) {
+		t.Errorf("expected '%s' error, received '%v'", expectedErr, err)
+	}
+}
+
+func decode(t *testing.T, definition string) schema.Resolver {
+	t.Helper()
+
+	schemaResolver := schema.NewBasicResolver()
+	if err := yaml.NewDecoder().Decode(strings.NewReader(definition), schemaResolver); err != nil {
+		t.Fatal(err)
+	}
+
+	return schemaResolver
+}
+
+// typeCheck checks that the generated code compiles
+func typeCheck(t *testing.T, code string) {
+	t.Helper()
+
+	fileSet := token.NewFileSet()
+
+	file, err := parser.ParseFile(fileSet, "events.go", code, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := types.Config{Importer: importer.ForCompiler(fileSet, "source", nil)}
+	if _, err := config.Check("events", fileSet, []*ast.File{file}, nil); err != nil {
+		t.Errorf("generated code doesn't type check: %v", err)
+	}
+}
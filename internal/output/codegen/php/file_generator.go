@@ -0,0 +1,468 @@
+// php package generates PHP 8.1 classes and enums of the lifecycle definitions
+package php
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/codegen"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+)
+
+const (
+	indentation = "    "
+
+	// typesNamespace is the namespace of the declared types, relative to the root namespace
+	typesNamespace = "Types"
+)
+
+/*
+FileGenerator writes a PHP file for each class and enum to a directory, following the PSR-4 layout of the root
+namespace. The objects are generated as final classes with readonly properties, the enums of strings and integers
+as backed enums and the arrays as PHP arrays typed by docblocks, like list<int>. The nullable properties are optional
+constructor parameters, declared after the required ones. The declared types are generated
+in the Types namespace and the published events in the namespace of their module, like Cake for the cake module
+*/
+type FileGenerator struct {
+	outDir    string
+	namespace string
+}
+
+func NewFileGenerator(outDir, namespace string) *FileGenerator {
+	return &FileGenerator{
+		outDir:    outDir,
+		namespace: strings.Trim(namespace, "\\"),
+	}
+}
+
+// Generate writes the files and returns the written file paths in declaration order
+func (f *FileGenerator) Generate(schemaResolver schema.Resolver) ([]string, error) {
+	declaredTypes, err := schemaResolver.GetTypes()
+	if err != nil {
+		return nil, fmt.Errorf("can't get types to generate: %w", err)
+	}
+
+	publishedEvents, err := schemaResolver.GetPublishedEvents()
+	if err != nil {
+		return nil, fmt.Errorf("can't get published events to generate: %w", err)
+	}
+
+	g := &generator{
+		declaredTypes:   codegen.NewDeclaredTypes(declaredTypes),
+		declaredByNames: make(map[string]types.TypeDescriber),
+		declared:        make(map[string]bool),
+		inlining:        make(map[string]bool),
+	}
+
+	for i := range declaredTypes {
+		g.declaredByNames[declaredTypes[i].Name()] = declaredTypes[i]
+	}
+
+	for _, typeDescriber := range declaredTypes {
+		if err := g.declare(typesNamespace, codegen.PascalCase(typeDescriber.Name()), typeDescriber); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, event := range publishedEvents {
+		name := codegen.PascalCase(event.Name()) + "Event"
+
+		if err := g.declare(moduleNamespace(event.Module()), name, codegen.EventEnvelope(event)); err != nil {
+			return nil, fmt.Errorf("can't generate published event '%s': %w", event.Name(), err)
+		}
+	}
+
+	paths := make([]string, len(g.files))
+
+	for i, file := range g.files {
+		directory := filepath.Join(f.outDir, filepath.Join(strings.Split(file.namespace, "\\")...))
+
+		if err := os.MkdirAll(directory, 0755); err != nil {
+			return nil, fmt.Errorf("can't create output directory '%s': %w", directory, err)
+		}
+
+		paths[i] = filepath.Join(directory, file.name+".php")
+
+		if err := os.WriteFile(paths[i], []byte(file.code(f.namespace)), 0644); err != nil {
+			return nil, fmt.Errorf("can't write file '%s': %w", paths[i], err)
+		}
+	}
+
+	return paths, nil
+}
+
+// file is a PHP file declaring a class or an enum. The namespace is relative to the root namespace
+type file struct {
+	namespace string
+	name      string
+	uses      map[string]bool
+	body      string
+}
+
+func (f *file) code(rootNamespace string) string {
+	var code strings.Builder
+	code.WriteString("<?php\n\n// " + codegen.GeneratedHeader + "\n\ndeclare(strict_types=1);\n\n")
+	code.WriteString("namespace " + joinNamespace(rootNamespace, f.namespace) + ";\n\n")
+
+	if len(f.uses) > 0 {
+		uses := make([]string, 0, len(f.uses))
+		for use := range f.uses {
+			uses = append(uses, joinNamespace(rootNamespace, use))
+		}
+
+		sort.Strings(uses)
+
+		for _, use := range uses {
+			code.WriteString("use " + use + ";\n")
+		}
+
+		code.WriteString("\n")
+	}
+
+	code.WriteString(f.body)
+
+	return code.String()
+}
+
+// phpType is the native type of a definition, with the type of the docblock when the native type is not specific
+type phpType struct {
+	native string
+	doc    string
+}
+
+func (p phpType) withNull(nullable bool) phpType {
+	if !nullable || strings.HasPrefix(p.native, "?") {
+		return p
+	}
+
+	return phpType{native: "?" + p.native, doc: p.doc + "|null"}
+}
+
+// varType returns the type of the docblock, empty when the native type is specific
+func (p phpType) varType() string {
+	if strings.Contains(p.doc, "<") {
+		return p.doc
+	}
+
+	return ""
+}
+
+type generator struct {
+	declaredTypes   *codegen.DeclaredTypes
+	declaredByNames map[string]types.TypeDescriber
+	files           []*file
+
+	// declared stores the declared classes and enums by their namespace and name, which are declared once
+	declared map[string]bool
+
+	// inlining stores the declared types being inlined, which can't be recursive since they have no name in PHP
+	inlining map[string]bool
+}
+
+/*
+declare generates the class of the objects and the enum of the enums. The other definitions have no declaration in PHP,
+they are inlined where they are used
+*/
+func (g *generator) declare(namespace, name string, typeDescriber types.TypeDescriber) error {
+	if _, isReference, err := g.declaredTypes.Reference(typeDescriber); err != nil || isReference {
+		return err
+	}
+
+	qualifiedName := joinNamespace(namespace, name)
+	if g.declared[qualifiedName] {
+		return nil
+	}
+
+	g.declared[qualifiedName] = true
+
+	switch describer := typeDescriber.(type) {
+	case types.ScalarDescriber:
+		if hasNativeEnum(describer) {
+			g.declareEnum(namespace, name, describer)
+		}
+	case types.ObjectDescriber:
+		return g.declareClass(namespace, name, describer)
+	}
+
+	return nil
+}
+
+func (g *generator) declareClass(namespace, name string, object types.ObjectDescriber) error {
+	classFile := &file{namespace: namespace, name: name, uses: make(map[string]bool)}
+	g.files = append(g.files, classFile)
+
+	var (
+		parameters strings.Builder
+		// The nullable properties can be absent, their parameters are optional and declared after the required ones
+		optionalParameters strings.Builder
+		serialization      strings.Builder
+		properties         = object.Properties()
+	)
+
+	for _, property := range properties {
+		propertyName := codegen.CamelCase(property.Name())
+
+		propertyType, err := g.propertyType(classFile, namespace, name+codegen.PascalCase(property.Name()), property)
+		if err != nil {
+			return err
+		}
+
+		if property.Nullable() {
+			optionalParameters.WriteString(docComment(g.declaredTypes.Description(property), propertyType.varType(), 2))
+			optionalParameters.WriteString(fmt.Sprintf("%spublic readonly %s $%s = null,\n", indent(2), propertyType.native, propertyName))
+		} else {
+			parameters.WriteString(docComment(g.declaredTypes.Description(property), propertyType.varType(), 2))
+			parameters.WriteString(fmt.Sprintf("%spublic readonly %s $%s,\n", indent(2), propertyType.native, propertyName))
+		}
+
+		serialization.WriteString(fmt.Sprintf("%s%s => $this->%s,\n", indent(3), quote(property.Name()), propertyName))
+	}
+
+	var body strings.Builder
+	body.WriteString(docComment(g.declaredTypes.Description(object), "", 0))
+	body.WriteString(fmt.Sprintf("final class %s implements \\JsonSerializable\n{\n", name))
+
+	if len(properties) > 0 {
+		body.WriteString(fmt.Sprintf(
+			"%spublic function __construct(\n%s%s%s) {\n%s}\n\n",
+			indent(1),
+			parameters.String(),
+			optionalParameters.String(),
+			indent(1),
+			indent(1),
+		))
+		body.WriteString(fmt.Sprintf("%spublic function jsonSerialize(): array\n%s{\n", indent(1), indent(1)))
+		body.WriteString(fmt.Sprintf("%sreturn [\n%s%s];\n%s}\n", indent(2), serialization.String(), indent(2), indent(1)))
+	} else {
+		// The empty arrays are encoded as JSON arrays, not as objects
+		body.WriteString(fmt.Sprintf("%spublic function jsonSerialize(): object\n%s{\n", indent(1), indent(1)))
+		body.WriteString(fmt.Sprintf("%sreturn new \\stdClass();\n%s}\n", indent(2), indent(1)))
+	}
+
+	body.WriteString("}\n")
+	classFile.body = body.String()
+
+	return nil
+}
+
+func (g *generator) declareEnum(namespace, name string, scalarType types.ScalarDescriber) {
+	backingType := "string"
+	if scalarType.Type() == types.ScalarIntegerType {
+		backingType = "int"
+	}
+
+	var body strings.Builder
+	body.WriteString(docComment(g.declaredTypes.Description(scalarType), "", 0))
+	body.WriteString(fmt.Sprintf("enum %s: %s\n{\n", name, backingType))
+
+	for _, value := range scalarType.Enum() {
+		switch value := value.(type) {
+		case string:
+			body.WriteString(fmt.Sprintf("%scase %s = %s;\n", indent(1), codegen.PascalCase(value), quote(value)))
+		case int:
+			caseName := "Value" + strings.ReplaceAll(strconv.Itoa(value), "-", "Minus")
+			body.WriteString(fmt.Sprintf("%scase %s = %d;\n", indent(1), caseName, value))
+		}
+	}
+
+	body.WriteString("}\n")
+
+	g.files = append(g.files, &file{namespace: namespace, name: name, body: body.String()})
+}
+
+// propertyType returns the type of the definition used by the file, declaring the nested objects and enums in the namespace
+func (g *generator) propertyType(currentFile *file, namespace, name string, typeDescriber types.TypeDescriber) (phpType, error) {
+	referenceName, isReference, err := g.declaredTypes.Reference(typeDescriber)
+	if err != nil {
+		return phpType{}, err
+	}
+
+	if isReference {
+		target := g.declaredByNames[referenceName]
+		nullable := typeDescriber.Nullable() || target.Nullable()
+
+		if hasDeclaration(target) {
+			return g.classType(currentFile, typesNamespace, codegen.PascalCase(referenceName)).withNull(nullable), nil
+		}
+
+		if g.inlining[referenceName] {
+			return phpType{}, fmt.Errorf(
+				"recursive definition '%s' has no PHP equivalent, it must be recursive through an object",
+				typeDescriber.Path(),
+			)
+		}
+
+		g.inlining[referenceName] = true
+		defer delete(g.inlining, referenceName)
+
+		// The nested definitions of the declared types are declared with them, once
+		targetType, err := g.propertyType(currentFile, typesNamespace, codegen.PascalCase(referenceName), target)
+		if err != nil {
+			return phpType{}, err
+		}
+
+		return targetType.withNull(nullable), nil
+	}
+
+	if hasDeclaration(typeDescriber) {
+		if err := g.declare(namespace, name, typeDescriber); err != nil {
+			return phpType{}, err
+		}
+
+		return g.classType(currentFile, namespace, name).withNull(typeDescriber.Nullable()), nil
+	}
+
+	switch describer := typeDescriber.(type) {
+	case types.ScalarDescriber:
+		scalarType, err := scalarTypeName(describer)
+		if err != nil {
+			return phpType{}, err
+		}
+
+		return phpType{native: scalarType, doc: scalarType}.withNull(describer.Nullable()), nil
+	case types.ArrayDescriber:
+		items, err := g.propertyType(currentFile, namespace, name+"Item", describer.Items())
+		if err != nil {
+			return phpType{}, err
+		}
+
+		return phpType{native: "array", doc: fmt.Sprintf("list<%s>", items.doc)}.withNull(describer.Nullable()), nil
+	}
+
+	return phpType{}, fmt.Errorf("type '%T' of definition '%s' is not supported", typeDescriber, typeDescriber.Path())
+}
+
+// classType returns the type of the class or enum, importing it when declared in another namespace
+func (g *generator) classType(currentFile *file, namespace, name string) phpType {
+	if namespace != currentFile.namespace {
+		currentFile.uses[joinNamespace(namespace, name)] = true
+	}
+
+	return phpType{native: name, doc: name}
+}
+
+// hasDeclaration reports whether the definition is declared as a class or enum
+func hasDeclaration(typeDescriber types.TypeDescriber) bool {
+	switch describer := typeDescriber.(type) {
+	case types.ScalarDescriber:
+		return hasNativeEnum(describer)
+	case types.ObjectDescriber:
+		return true
+	}
+
+	return false
+}
+
+// hasNativeEnum reports whether the enum can be declared as a backed enum, which only supports strings and integers
+func hasNativeEnum(scalarType types.ScalarDescriber) bool {
+	if !scalarType.HasEnum() {
+		return false
+	}
+
+	for _, value := range scalarType.Enum() {
+		switch value.(type) {
+		case string:
+			if scalarType.Type() != types.ScalarStringType {
+				return false
+			}
+		case int:
+			if scalarType.Type() != types.ScalarIntegerType {
+				return false
+			}
+		case nil:
+			// The null value is declared by the nullable type
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func scalarTypeName(scalarType types.ScalarDescriber) (string, error) {
+	switch scalarType.Type() {
+	case types.ScalarStringType:
+		return "string", nil
+	case types.ScalarIntegerType:
+		return "int", nil
+	case types.ScalarNumberType:
+		return "float", nil
+	case types.ScalarBooleanType:
+		return "bool", nil
+	}
+
+	return "", fmt.Errorf("type '%s' of definition '%s' is not supported", scalarType.Type(), scalarType.Path())
+}
+
+// moduleNamespace returns the namespace of the module, like Cake\Sales for the cake/sales module
+func moduleNamespace(module string) string {
+	segments := strings.FieldsFunc(module, func(r rune) bool {
+		return r == '/' || r == '\\' || r == '.'
+	})
+
+	for i := range segments {
+		segments[i] = codegen.PascalCase(segments[i])
+	}
+
+	return strings.Join(segments, "\\")
+}
+
+func joinNamespace(namespaces ...string) string {
+	var segments []string
+
+	for _, namespace := range namespaces {
+		if len(namespace) > 0 {
+			segments = append(segments, namespace)
+		}
+	}
+
+	return strings.Join(segments, "\\")
+}
+
+func docComment(description, varType string, depth int) string {
+	var lines []string
+
+	if description = strings.TrimSpace(strings.ReplaceAll(description, "*/", "*\\/")); len(description) > 0 {
+		lines = strings.Split(description, "\n")
+	}
+
+	if len(varType) > 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+
+		lines = append(lines, "@var "+varType)
+	}
+
+	switch len(lines) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("%s/** %s */\n", indent(depth), lines[0])
+	}
+
+	var comment strings.Builder
+	comment.WriteString(indent(depth) + "/**\n")
+
+	for _, line := range lines {
+		comment.WriteString(strings.TrimRight(fmt.Sprintf("%s * %s", indent(depth), line), " ") + "\n")
+	}
+
+	comment.WriteString(indent(depth) + " */\n")
+
+	return comment.String()
+}
+
+// quote quotes the value as a PHP single quoted string
+func quote(value string) string {
+	return "'" + strings.NewReplacer("\\", "\\\\", "'", "\\'").Replace(value) + "'"
+}
+
+func indent(depth int) string {
+	return strings.Repeat(indentation, depth)
+}
@@ -0,0 +1,214 @@
+package php_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/codegen/php"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+)
+
+func TestShouldGeneratePHPFiles(t *testing.T) {
+	schemaResolver := schema.NewBasicResolver()
+	err := yaml.NewDecoder().Decode(strings.NewReader(`
+version: "1.0"
+name: super-cool-service
+
+events:
+  published:
+    CAKE_BURNED:
+      visibility: public
+      module: cake/kitchen
+      description: Evento disparado quando o bolo é queimado
+      attributes:
+        type: object
+        properties:
+          cake:
+            $ref: '#/types/Cake'
+          burned-at:
+            type: string
+            format: date-time
+            value: "2022-10-20T10:00:00Z"
+          intensity:
+            type: string
+            nullable: true
+            enum: [low, high]
+            value: low
+      entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+            format: uuid
+            value: 41af6672-5b3a-4d5c-9be1-7c93dc1614e1
+
+types:
+  CakeShape:
+    description: Enum dos formatos de bolo suportado
+    type: string
+    nullable: true
+    enum:
+      - squad
+      - circle
+    value: circle
+
+  Layers:
+    type: array
+    items:
+      type: integer
+      enum: [1, 2]
+      value: 1
+
+  Cake:
+    description: |-
+      Representa um bolo
+      com camadas
+    type: object
+    properties:
+      shape:
+        $ref: '#/types/CakeShape'
+      tags:
+        type: array
+        nullable: true
+        items:
+          type: string
+          value: chocolate
+      layers:
+        $ref: '#/types/Layers'
+        description: Camadas do bolo`), schemaResolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := t.TempDir()
+
+	paths, err := php.NewFileGenerator(outDir, "\\App\\Events\\").Generate(schemaResolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPaths := []string{
+		filepath.Join(outDir, "Types", "CakeShape.php"),
+		filepath.Join(outDir, "Types", "Cake.php"),
+		filepath.Join(outDir, "Types", "LayersItem.php"),
+		filepath.Join(outDir, "Cake", "Kitchen", "CakeBurnedEvent.php"),
+		filepath.Join(outDir, "Cake", "Kitchen", "CakeBurnedEventAttributes.php"),
+		filepath.Join(outDir, "Cake", "Kitchen", "CakeBurnedEventAttributesIntensity.php"),
+		filepath.Join(outDir, "Cake", "Kitchen", "CakeBurnedEventEntities.php"),
+	}
+
+	if !reflect.DeepEqual(paths, expectedPaths) {
+		t.Fatalf("expected '%v' paths, received '%v'", expectedPaths, paths)
+	}
+
+	for path, expected := range map[string]string{
+		expectedPaths[0]: `<?php
+
+// Code generated by lifecycledoc. DO NOT EDIT.
+
+declare(strict_types=1);
+
+namespace App\Events\Types;
+
+/** Enum dos formatos de bolo suportado */
+enum CakeShape: string
+{
+    case Squad = 'squad';
+    case Circle = 'circle';
+}
+`,
+		expectedPaths[1]: `<?php
+
+// Code generated by lifecycledoc. DO NOT EDIT.
+
+declare(strict_types=1);
+
+namespace App\Events\Types;
+
+/**
+ * Representa um bolo
+ * com camadas
+ */
+final class Cake implements \JsonSerializable
+{
+    public function __construct(
+        public readonly ?CakeShape $shape,
+        /**
+         * Camadas do bolo
+         *
+         * @var list<LayersItem>
+         */
+        public readonly array $layers,
+        /** @var list<string>|null */
+        public readonly ?array $tags = null,
+    ) {
+    }
+
+    public function jsonSerialize(): array
+    {
+        return [
+            'shape' => $this->shape,
+            'tags' => $this->tags,
+            'layers' => $this->layers,
+        ];
+    }
+}
+`,
+		expectedPaths[2]: `<?php
+
+// Code generated by lifecycledoc. DO NOT EDIT.
+
+declare(strict_types=1);
+
+namespace App\Events\Types;
+
+enum LayersItem: int
+{
+    case Value1 = 1;
+    case Value2 = 2;
+}
+`,
+		expectedPaths[4]: `<?php
+
+// Code generated by lifecycledoc. DO NOT EDIT.
+
+declare(strict_types=1);
+
+namespace App\Events\Cake\Kitchen;
+
+use App\Events\Types\Cake;
+
+final class CakeBurnedEventAttributes implements \JsonSerializable
+{
+    public function __construct(
+        public readonly Cake $cake,
+        public readonly string $burnedAt,
+        public readonly ?CakeBurnedEventAttributesIntensity $intensity = null,
+    ) {
+    }
+
+    public function jsonSerialize(): array
+    {
+        return [
+            'cake' => $this->cake,
+            'burned-at' => $this->burnedAt,
+            'intensity' => $this->intensity,
+        ];
+    }
+}
+`,
+	} {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(content) != expected {
+			t.Errorf("expected '%s' in file '%s', received '%s'", expected, path, content)
+		}
+	}
+}
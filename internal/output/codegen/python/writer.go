@@ -0,0 +1,494 @@
+// python package generates pydantic models of the lifecycle definitions
+package python
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/codegen"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+)
+
+const indentation = "    "
+
+// stringFormats are the Python types, and their modules, of the string formats
+var stringFormats = map[string][2]string{
+	"date-time": {"datetime", "datetime.datetime"},
+	"date":      {"datetime", "datetime.date"},
+	"time":      {"datetime", "datetime.time"},
+	"uuid":      {"uuid", "uuid.UUID"},
+}
+
+// keywords are the Python keywords, which can't be used as field names
+var keywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true, "async": true,
+	"await": true, "break": true, "class": true, "continue": true, "def": true, "del": true, "elif": true,
+	"else": true, "except": true, "finally": true, "for": true, "from": true, "global": true, "if": true,
+	"import": true, "in": true, "is": true, "lambda": true, "nonlocal": true, "not": true, "or": true,
+	"pass": true, "raise": true, "return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+/*
+Writer writes the pydantic models of the declared types and of the published events. The objects are declared
+as models with the fields in snake_case, aliased by the property names, the enums as literals and the nullable
+definitions as optionals. The fields of the nullable properties default to None, since they can be absent. The
+published events are declared as models named by the event with the "Event" suffix, like CakeBurnedEvent. The nested
+objects are declared as models named by their parent and property, like CakeBurnedEventAttributes
+*/
+type Writer struct{}
+
+func NewWriter() *Writer {
+	return &Writer{}
+}
+
+func (w *Writer) Write(out io.Writer, schemaResolver schema.Resolver) error {
+	declaredTypes, err := schemaResolver.GetTypes()
+	if err != nil {
+		return fmt.Errorf("can't get types to generate: %w", err)
+	}
+
+	publishedEvents, err := schemaResolver.GetPublishedEvents()
+	if err != nil {
+		return fmt.Errorf("can't get published events to generate: %w", err)
+	}
+
+	g := &generator{
+		declaredTypes:   codegen.NewDeclaredTypes(declaredTypes),
+		declaredByNames: make(map[string]types.TypeDescriber),
+		aliases:         make(map[string]*alias),
+		imports:         map[string]bool{"typing": true},
+	}
+
+	for i := range declaredTypes {
+		g.declaredByNames[declaredTypes[i].Name()] = declaredTypes[i]
+	}
+
+	for _, typeDescriber := range declaredTypes {
+		if err := g.declare(codegen.PascalCase(typeDescriber.Name()), typeDescriber); err != nil {
+			return err
+		}
+	}
+
+	for _, event := range publishedEvents {
+		if err := g.declare(codegen.PascalCase(event.Name())+"Event", codegen.EventEnvelope(event)); err != nil {
+			return fmt.Errorf("can't generate published event '%s': %w", event.Name(), err)
+		}
+	}
+
+	var code strings.Builder
+	code.WriteString("# " + codegen.GeneratedHeader + "\n\n")
+	// The postponed annotations allow the models to reference the models declared after them
+	code.WriteString("from __future__ import annotations\n\n")
+
+	imports := make([]string, 0, len(g.imports))
+	for module := range g.imports {
+		imports = append(imports, module)
+	}
+
+	sort.Strings(imports)
+
+	for _, module := range imports {
+		code.WriteString("import " + module + "\n")
+	}
+
+	code.WriteString("\nfrom pydantic import BaseModel, ConfigDict, Field\n")
+
+	aliases, err := g.sortedAliases()
+	if err != nil {
+		return err
+	}
+
+	// The aliases are declared before the models using them, so the module reads top-down
+	if len(aliases) > 0 {
+		code.WriteString("\n")
+	}
+
+	for i := range aliases {
+		code.WriteString("\n" + aliases[i])
+	}
+
+	for i := range g.models {
+		code.WriteString("\n\n" + g.models[i])
+	}
+
+	if _, err := io.WriteString(out, code.String()); err != nil {
+		return fmt.Errorf("can't write python code: %w", err)
+	}
+
+	return nil
+}
+
+// alias is a type alias declaration, with the names of the declared types referenced by it
+type alias struct {
+	code       string
+	references []string
+}
+
+type generator struct {
+	declaredTypes   *codegen.DeclaredTypes
+	declaredByNames map[string]types.TypeDescriber
+	models          []string
+	aliases         map[string]*alias
+	aliasNames      []string
+	imports         map[string]bool
+
+	// references stores the declared types referenced by the type expressions
+	references []string
+
+	/*
+		forwardReferences reports whether the models are referenced by string, as the aliases are evaluated when
+		declared, before the models
+	*/
+	forwardReferences bool
+}
+
+// declare declares the objects as models and the other definitions as type aliases
+func (g *generator) declare(name string, typeDescriber types.TypeDescriber) error {
+	description := g.declaredTypes.Description(typeDescriber)
+
+	if _, isReference, err := g.declaredTypes.Reference(typeDescriber); err != nil {
+		return err
+	} else if object, isObject := typeDescriber.(types.ObjectDescriber); isObject && !isReference {
+		index := len(g.models)
+		g.models = append(g.models, "")
+
+		// The references of the nested models are not references of the alias being declared
+		references, forwardReferences := g.references, g.forwardReferences
+		g.forwardReferences = false
+
+		model, err := g.model(name, description, object)
+		if err != nil {
+			return err
+		}
+
+		g.models[index] = model
+		g.references, g.forwardReferences = references, forwardReferences
+
+		return nil
+	}
+
+	g.references = nil
+	g.forwardReferences = true
+
+	expression, err := g.typeExpression(name, typeDescriber)
+	g.forwardReferences = false
+
+	if err != nil {
+		return err
+	}
+
+	g.aliases[name] = &alias{
+		code:       comment(description) + fmt.Sprintf("%s = %s\n", name, expression),
+		references: g.references,
+	}
+	g.aliasNames = append(g.aliasNames, name)
+
+	return nil
+}
+
+/*
+sortedAliases returns the aliases declared after the aliases referenced by them, since the aliases are evaluated
+when declared, unlike the annotations of the models
+*/
+func (g *generator) sortedAliases() ([]string, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	var (
+		sorted []string
+		states = make(map[string]int)
+		visit  func(name string) error
+	)
+
+	visit = func(name string) error {
+		declaration, isAlias := g.aliases[name]
+		if !isAlias || states[name] == visited {
+			return nil
+		}
+
+		if states[name] == visiting {
+			return fmt.Errorf("recursive type '%s' must be recursive through an object to be generated", name)
+		}
+
+		states[name] = visiting
+
+		for _, reference := range declaration.references {
+			if err := visit(reference); err != nil {
+				return err
+			}
+		}
+
+		states[name] = visited
+		sorted = append(sorted, declaration.code)
+
+		return nil
+	}
+
+	for _, name := range g.aliasNames {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
+
+func (g *generator) model(name, description string, object types.ObjectDescriber) (string, error) {
+	var (
+		fields  strings.Builder
+		aliased bool
+		names   = make(map[string]string)
+	)
+
+	for _, property := range object.Properties() {
+		field := fieldName(property.Name())
+
+		if propertyName, exists := names[field]; exists {
+			return "", fmt.Errorf(
+				"field name '%s' of definition '%s' is already used by property '%s'",
+				field,
+				property.Path(),
+				propertyName,
+			)
+		}
+
+		names[field] = property.Name()
+
+		expression, err := g.typeExpression(name+codegen.PascalCase(property.Name()), property)
+		if err != nil {
+			return "", err
+		}
+
+		var arguments []string
+
+		// The nullable properties can be absent
+		if property.Nullable() {
+			arguments = append(arguments, "default=None")
+		}
+
+		if field != property.Name() {
+			aliased = true
+			arguments = append(arguments, "alias="+quote(property.Name()))
+		}
+
+		if description := g.declaredTypes.Description(property); len(description) > 0 {
+			arguments = append(arguments, "description="+quote(description))
+		}
+
+		fields.WriteString(indentation + field + ": " + expression)
+
+		switch {
+		case len(arguments) == 1 && property.Nullable():
+			fields.WriteString(" = None")
+		case len(arguments) > 0:
+			fields.WriteString(" = Field(" + strings.Join(arguments, ", ") + ")")
+		}
+
+		fields.WriteString("\n")
+	}
+
+	var model strings.Builder
+	model.WriteString(fmt.Sprintf("class %s(BaseModel):\n", name))
+
+	if len(description) > 0 {
+		model.WriteString(docstring(description) + "\n")
+	}
+
+	// The aliased fields can also be populated by their names, allowing to create the models with the fields in snake_case
+	if aliased {
+		model.WriteString(indentation + "model_config = ConfigDict(populate_by_name=True)\n\n")
+	}
+
+	if fields.Len() < 1 {
+		fields.WriteString(indentation + "pass\n")
+	}
+
+	model.WriteString(fields.String())
+
+	return model.String(), nil
+}
+
+// typeExpression returns the type of the definition, declaring the nested objects with the name
+func (g *generator) typeExpression(name string, typeDescriber types.TypeDescriber) (string, error) {
+	referenceName, isReference, err := g.declaredTypes.Reference(typeDescriber)
+	if err != nil {
+		return "", err
+	}
+
+	if isReference {
+		name := codegen.PascalCase(referenceName)
+		g.references = append(g.references, name)
+
+		// The models can't be nullable by themselves, unlike the aliases
+		target := g.declaredByNames[referenceName]
+		if _, isModel := target.(types.ObjectDescriber); isModel {
+			return optional(g.modelName(name), typeDescriber.Nullable() || target.Nullable()), nil
+		}
+
+		return optional(name, typeDescriber.Nullable()), nil
+	}
+
+	switch describer := typeDescriber.(type) {
+	case types.ScalarDescriber:
+		return g.scalarExpression(describer)
+	case types.ArrayDescriber:
+		items, err := g.typeExpression(name+"Item", describer.Items())
+		if err != nil {
+			return "", err
+		}
+
+		return optional(fmt.Sprintf("typing.List[%s]", items), describer.Nullable()), nil
+	case types.ObjectDescriber:
+		if err := g.declare(name, describer); err != nil {
+			return "", err
+		}
+
+		return optional(g.modelName(name), describer.Nullable()), nil
+	}
+
+	return "", fmt.Errorf("type '%T' of definition '%s' is not supported", typeDescriber, typeDescriber.Path())
+}
+
+func (g *generator) scalarExpression(scalarType types.ScalarDescriber) (string, error) {
+	if scalarType.HasEnum() {
+		var literals []string
+
+		for _, value := range scalarType.Enum() {
+			// The null value is declared by the optional type
+			if value == nil {
+				continue
+			}
+
+			literal, err := literal(value)
+			if err != nil {
+				return "", fmt.Errorf("can't generate enum of definition '%s': %w", scalarType.Path(), err)
+			}
+
+			literals = append(literals, literal)
+		}
+
+		return optional(fmt.Sprintf("typing.Literal[%s]", strings.Join(literals, ", ")), scalarType.Nullable()), nil
+	}
+
+	var expression string
+
+	switch scalarType.Type() {
+	case types.ScalarStringType:
+		expression = "str"
+
+		if format, exists := stringFormats[scalarType.Format()]; exists {
+			g.imports[format[0]] = true
+			expression = format[1]
+		}
+	case types.ScalarIntegerType:
+		expression = "int"
+	case types.ScalarNumberType:
+		expression = "float"
+	case types.ScalarBooleanType:
+		expression = "bool"
+	default:
+		return "", fmt.Errorf("type '%s' of definition '%s' is not supported", scalarType.Type(), scalarType.Path())
+	}
+
+	return optional(expression, scalarType.Nullable()), nil
+}
+
+// modelName returns the name of the model, as a forward reference when the models are referenced by string
+func (g *generator) modelName(name string) string {
+	if g.forwardReferences {
+		return quote(name)
+	}
+
+	return name
+}
+
+// literal returns the Python literal of the enum value
+func literal(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case string:
+		return quote(value), nil
+	case bool:
+		if value {
+			return "True", nil
+		}
+
+		return "False", nil
+	}
+
+	literal, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(literal), nil
+}
+
+// fieldName returns the field name in snake_case, which can't be a keyword or start with underscore like the private attributes
+func fieldName(propertyName string) string {
+	name := codegen.SnakeCase(propertyName)
+
+	if keywords[name] {
+		return name + "_"
+	}
+
+	if strings.HasPrefix(name, "_") {
+		return "field" + name
+	}
+
+	return name
+}
+
+func optional(expression string, nullable bool) string {
+	if nullable && !strings.HasPrefix(expression, "typing.Optional[") {
+		return "typing.Optional[" + expression + "]"
+	}
+
+	return expression
+}
+
+func quote(value string) string {
+	return strconv.Quote(value)
+}
+
+func docstring(description string) string {
+	// The quotes are escaped, since a quote at the end of the description would close the docstring
+	description = strings.NewReplacer("\\", "\\\\", `"`, `\"`).Replace(strings.TrimSpace(description))
+	lines := strings.Split(description, "\n")
+
+	if len(lines) == 1 {
+		return fmt.Sprintf("%s\"\"\"%s\"\"\"\n", indentation, lines[0])
+	}
+
+	var docstring strings.Builder
+	docstring.WriteString(indentation + "\"\"\"\n")
+
+	for _, line := range lines {
+		docstring.WriteString(strings.TrimRight(indentation+line, " ") + "\n")
+	}
+
+	docstring.WriteString(indentation + "\"\"\"\n")
+
+	return docstring.String()
+}
+
+func comment(description string) string {
+	description = strings.TrimSpace(description)
+	if len(description) < 1 {
+		return ""
+	}
+
+	var comment strings.Builder
+
+	for _, line := range strings.Split(description, "\n") {
+		comment.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+	}
+
+	return comment.String()
+}
@@ -0,0 +1,194 @@
+package python_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/codegen/python"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+)
+
+func TestShouldWritePydanticModels(t *testing.T) {
+	schemaResolver := schema.NewBasicResolver()
+	err := yaml.NewDecoder().Decode(strings.NewReader(`
+version: "1.0"
+name: super-cool-service
+
+events:
+  published:
+    CAKE_BURNED:
+      visibility: public
+      description: Evento disparado quando o bolo é "queimado"
+      attributes:
+        type: object
+        properties:
+          cake:
+            $ref: '#/types/Cake'
+          burned-at:
+            type: string
+            format: date-time
+            value: "2022-10-20T10:00:00Z"
+      entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+            format: uuid
+            value: 41af6672-5b3a-4d5c-9be1-7c93dc1614e1
+
+types:
+  Layers:
+    type: array
+    items:
+      $ref: '#/types/CakeShape'
+
+  Cakes:
+    type: array
+    items:
+      $ref: '#/types/Cake'
+
+  CakeShape:
+    description: Enum dos formatos de bolo suportado
+    type: string
+    nullable: true
+    enum:
+      - squad
+      - circle
+    value: circle
+
+  Cake:
+    description: |-
+      Representa um bolo
+      com camadas
+    type: object
+    properties:
+      layers:
+        $ref: '#/types/Layers'
+        description: Camadas do bolo
+      weight:
+        type: number
+        description: Peso do bolo
+        nullable: true
+        value: 1.5
+      from:
+        type: boolean
+        enum: [true]
+        value: true
+      category:
+        $ref: '#/types/Category'
+
+  Category:
+    type: object
+    nullable: true
+    properties:
+      parent:
+        $ref: '#/types/Category'
+        nullable: true`), schemaResolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var code strings.Builder
+	if err := python.NewWriter().Write(&code, schemaResolver); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# Code generated by lifecycledoc. DO NOT EDIT.
+
+from __future__ import annotations
+
+import datetime
+import typing
+import uuid
+
+from pydantic import BaseModel, ConfigDict, Field
+
+
+# Enum dos formatos de bolo suportado
+CakeShape = typing.Optional[typing.Literal["squad", "circle"]]
+
+Layers = typing.List[CakeShape]
+
+Cakes = typing.List["Cake"]
+
+
+class Cake(BaseModel):
+    """
+    Representa um bolo
+    com camadas
+    """
+
+    model_config = ConfigDict(populate_by_name=True)
+
+    layers: Layers = Field(description="Camadas do bolo")
+    weight: typing.Optional[float] = Field(default=None, description="Peso do bolo")
+    from_: typing.Literal[True] = Field(alias="from")
+    category: typing.Optional[Category]
+
+
+class Category(BaseModel):
+    parent: typing.Optional[Category] = None
+
+
+class CakeBurnedEvent(BaseModel):
+    """Evento disparado quando o bolo é \"queimado\""""
+
+    attributes: CakeBurnedEventAttributes
+    entities: CakeBurnedEventEntities
+
+
+class CakeBurnedEventAttributes(BaseModel):
+    model_config = ConfigDict(populate_by_name=True)
+
+    cake: Cake
+    burned_at: datetime.datetime = Field(alias="burned-at")
+
+
+class CakeBurnedEventEntities(BaseModel):
+    model_config = ConfigDict(populate_by_name=True)
+
+    cake_id: uuid.UUID = Field(alias="cakeId")
+`
+
+	if code.String() != expected {
+		t.Errorf("expected '%s', received '%s'", expected, code.String())
+	}
+}
+
+func TestShouldReportFieldNameCollisions(t *testing.T) {
+	schemaResolver := schema.NewBasicResolver()
+	err := yaml.NewDecoder().Decode(strings.NewReader(`
+version: "1.0"
+name: super-cool-service
+
+events:
+  published:
+    CAKE_BURNED:
+      visibility: public
+      attributes:
+        type: object
+        properties:
+          cake_id:
+            type: string
+            value: "12354"
+          cakeId:
+            type: string
+            value: "12354"
+      entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+            value: "12354"`), schemaResolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedErr := "field name 'cake_id' of definition '#/events/published/CAKE_BURNED/attributes/properties/cakeId' is already used by property 'cake_id'"
+
+	err = python.NewWriter().Write(&strings.Builder{}, schemaResolver)
+	if err == nil || !strings.HasSuffix(err.Error(), expectedErr) {
+		t.Errorf("expected '%s' error, received '%v'", expectedErr, err)
+	}
+}
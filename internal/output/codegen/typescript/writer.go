@@ -0,0 +1,411 @@
+// typescript package generates TypeScript types, and optionally zod schemas, of the lifecycle definitions
+package typescript
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/codegen"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+)
+
+const indentation = "  "
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// zodStringFormats are the zod validations of the string formats
+var zodStringFormats = map[string]string{
+	"date-time": ".datetime()",
+	"email":     ".email()",
+	"uri":       ".url()",
+	"url":       ".url()",
+	"uuid":      ".uuid()",
+}
+
+/*
+Writer writes the TypeScript types of the declared types and of the published events. The objects are declared
+as interfaces, the arrays as "T[]", the enums as unions of literals and the nullable definitions as "T | null".
+The nullable properties are also optional, since they can be absent or null.
+The published events are declared as interfaces named by the event with the "Event" suffix, like CakeBurnedEvent
+*/
+type Writer struct {
+	// zod enables the zod schemas of the types, named by the type with the "Schema" suffix
+	zod bool
+}
+
+func NewWriter(zod bool) *Writer {
+	return &Writer{
+		zod: zod,
+	}
+}
+
+func (w *Writer) Write(out io.Writer, schemaResolver schema.Resolver) error {
+	declaredTypes, err := schemaResolver.GetTypes()
+	if err != nil {
+		return fmt.Errorf("can't get types to generate: %w", err)
+	}
+
+	publishedEvents, err := schemaResolver.GetPublishedEvents()
+	if err != nil {
+		return fmt.Errorf("can't get published events to generate: %w", err)
+	}
+
+	g := &generator{declaredTypes: codegen.NewDeclaredTypes(declaredTypes)}
+
+	var code strings.Builder
+	code.WriteString("// " + codegen.GeneratedHeader + "\n")
+
+	if w.zod {
+		code.WriteString("\nimport { z } from \"zod\";\n")
+	}
+
+	for _, typeDescriber := range declaredTypes {
+		declaration, err := g.typeDeclaration(codegen.PascalCase(typeDescriber.Name()), typeDescriber)
+		if err != nil {
+			return err
+		}
+
+		code.WriteString("\n" + declaration)
+	}
+
+	for _, event := range publishedEvents {
+		declaration, err := g.typeDeclaration(eventTypeName(event), codegen.EventEnvelope(event))
+		if err != nil {
+			return fmt.Errorf("can't generate published event '%s': %w", event.Name(), err)
+		}
+
+		code.WriteString("\n" + declaration)
+	}
+
+	if w.zod {
+		for _, typeDescriber := range declaredTypes {
+			declaration, err := g.zodDeclaration(codegen.PascalCase(typeDescriber.Name()), typeDescriber)
+			if err != nil {
+				return err
+			}
+
+			code.WriteString("\n" + declaration)
+		}
+
+		for _, event := range publishedEvents {
+			declaration, err := g.zodDeclaration(eventTypeName(event), codegen.EventEnvelope(event))
+			if err != nil {
+				return fmt.Errorf("can't generate published event '%s': %w", event.Name(), err)
+			}
+
+			code.WriteString("\n" + declaration)
+		}
+	}
+
+	if _, err := io.WriteString(out, code.String()); err != nil {
+		return fmt.Errorf("can't write typescript code: %w", err)
+	}
+
+	return nil
+}
+
+type generator struct {
+	declaredTypes *codegen.DeclaredTypes
+}
+
+// typeDeclaration declares the objects as interfaces and the other definitions as type aliases
+func (g *generator) typeDeclaration(name string, typeDescriber types.TypeDescriber) (string, error) {
+	expression, err := g.typeExpression(typeDescriber, 0)
+	if err != nil {
+		return "", err
+	}
+
+	declaration := docComment(g.declaredTypes.Description(typeDescriber), 0)
+
+	if _, isReference, _ := g.declaredTypes.Reference(typeDescriber); !isReference && !typeDescriber.Nullable() {
+		if _, isObject := typeDescriber.(types.ObjectDescriber); isObject {
+			return fmt.Sprintf("%sexport interface %s %s\n", declaration, name, expression), nil
+		}
+	}
+
+	return fmt.Sprintf("%sexport type %s = %s;\n", declaration, name, expression), nil
+}
+
+func (g *generator) typeExpression(typeDescriber types.TypeDescriber, depth int) (string, error) {
+	name, isReference, err := g.declaredTypes.Reference(typeDescriber)
+	if err != nil {
+		return "", err
+	}
+
+	if isReference {
+		return withNull(codegen.PascalCase(name), typeDescriber.Nullable()), nil
+	}
+
+	switch typeDescriber := typeDescriber.(type) {
+	case types.ScalarDescriber:
+		return g.scalarExpression(typeDescriber)
+	case types.ArrayDescriber:
+		items, err := g.typeExpression(typeDescriber.Items(), depth)
+		if err != nil {
+			return "", err
+		}
+
+		if strings.Contains(items, " | ") {
+			items = "(" + items + ")"
+		}
+
+		return withNull(items+"[]", typeDescriber.Nullable()), nil
+	case types.ObjectDescriber:
+		properties := typeDescriber.Properties()
+		if len(properties) < 1 {
+			return withNull("{}", typeDescriber.Nullable()), nil
+		}
+
+		var expression strings.Builder
+		expression.WriteString("{\n")
+
+		for _, property := range properties {
+			propertyExpression, err := g.typeExpression(property, depth+1)
+			if err != nil {
+				return "", err
+			}
+
+			expression.WriteString(docComment(g.declaredTypes.Description(property), depth+1))
+			expression.WriteString(fmt.Sprintf(
+				"%s%s%s: %s;\n",
+				indent(depth+1),
+				propertyKey(property.Name()),
+				optionalMark(property.Nullable()),
+				propertyExpression,
+			))
+		}
+
+		expression.WriteString(indent(depth) + "}")
+
+		return withNull(expression.String(), typeDescriber.Nullable()), nil
+	}
+
+	return "", fmt.Errorf("type '%T' of definition '%s' is not supported", typeDescriber, typeDescriber.Path())
+}
+
+func (g *generator) scalarExpression(scalarType types.ScalarDescriber) (string, error) {
+	if !scalarType.HasEnum() {
+		return withNull(scalarTypeName(scalarType.Type()), scalarType.Nullable()), nil
+	}
+
+	var literals []string
+
+	for _, value := range scalarType.Enum() {
+		// The null value is declared by the nullable type
+		if value == nil {
+			continue
+		}
+
+		literal, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("can't generate enum of definition '%s': %w", scalarType.Path(), err)
+		}
+
+		literals = append(literals, string(literal))
+	}
+
+	return withNull(strings.Join(literals, " | "), scalarType.Nullable()), nil
+}
+
+// zodDeclaration declares the zod schema typed by the TypeScript type, which allows the recursive schemas
+func (g *generator) zodDeclaration(name string, typeDescriber types.TypeDescriber) (string, error) {
+	expression, err := g.zodExpression(typeDescriber, 0)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("export const %sSchema: z.ZodType<%s> = %s;\n", name, name, expression), nil
+}
+
+func (g *generator) zodExpression(typeDescriber types.TypeDescriber, depth int) (string, error) {
+	name, isReference, err := g.declaredTypes.Reference(typeDescriber)
+	if err != nil {
+		return "", err
+	}
+
+	// The lazy schemas can be used before their declaration, like in recursive types
+	if isReference {
+		return withNullable(fmt.Sprintf("z.lazy(() => %sSchema)", codegen.PascalCase(name)), typeDescriber.Nullable()), nil
+	}
+
+	switch typeDescriber := typeDescriber.(type) {
+	case types.ScalarDescriber:
+		return g.zodScalarExpression(typeDescriber)
+	case types.ArrayDescriber:
+		items, err := g.zodExpression(typeDescriber.Items(), depth)
+		if err != nil {
+			return "", err
+		}
+
+		return withNullable(fmt.Sprintf("z.array(%s)", items), typeDescriber.Nullable()), nil
+	case types.ObjectDescriber:
+		var expression strings.Builder
+		expression.WriteString("z.object({\n")
+
+		for _, property := range typeDescriber.Properties() {
+			propertyExpression, err := g.zodExpression(property, depth+1)
+			if err != nil {
+				return "", err
+			}
+
+			expression.WriteString(fmt.Sprintf(
+				"%s%s: %s,\n",
+				indent(depth+1),
+				propertyKey(property.Name()),
+				withOptional(propertyExpression, property.Nullable()),
+			))
+		}
+
+		expression.WriteString(indent(depth) + "})")
+
+		return withNullable(expression.String(), typeDescriber.Nullable()), nil
+	}
+
+	return "", fmt.Errorf("type '%T' of definition '%s' is not supported", typeDescriber, typeDescriber.Path())
+}
+
+func (g *generator) zodScalarExpression(scalarType types.ScalarDescriber) (string, error) {
+	if !scalarType.HasEnum() {
+		var expression string
+
+		switch scalarType.Type() {
+		case types.ScalarStringType:
+			expression = "z.string()" + zodStringFormats[scalarType.Format()]
+		case types.ScalarIntegerType:
+			expression = "z.number().int()"
+		case types.ScalarNumberType:
+			expression = "z.number()"
+		case types.ScalarBooleanType:
+			expression = "z.boolean()"
+		default:
+			return "", fmt.Errorf("type '%s' of definition '%s' is not supported", scalarType.Type(), scalarType.Path())
+		}
+
+		return withNullable(expression, scalarType.Nullable()), nil
+	}
+
+	var (
+		literals    []string
+		onlyStrings = true
+	)
+
+	for _, value := range scalarType.Enum() {
+		if value == nil {
+			continue
+		}
+
+		literal, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("can't generate enum of definition '%s': %w", scalarType.Path(), err)
+		}
+
+		literals = append(literals, string(literal))
+		_, isString := value.(string)
+		onlyStrings = onlyStrings && isString
+	}
+
+	var expression string
+
+	switch {
+	case onlyStrings:
+		expression = fmt.Sprintf("z.enum([%s])", strings.Join(literals, ", "))
+	case len(literals) == 1:
+		expression = fmt.Sprintf("z.literal(%s)", literals[0])
+	default:
+		for i := range literals {
+			literals[i] = fmt.Sprintf("z.literal(%s)", literals[i])
+		}
+
+		expression = fmt.Sprintf("z.union([%s])", strings.Join(literals, ", "))
+	}
+
+	return withNullable(expression, scalarType.Nullable()), nil
+}
+
+func eventTypeName(event *types.PublishedEvent) string {
+	return codegen.PascalCase(event.Name()) + "Event"
+}
+
+func scalarTypeName(scalarType string) string {
+	switch scalarType {
+	case types.ScalarIntegerType, types.ScalarNumberType:
+		return "number"
+	}
+
+	return scalarType
+}
+
+// propertyKey quotes the property names that are not valid identifiers
+func propertyKey(name string) string {
+	if identifierRegexp.MatchString(name) {
+		return name
+	}
+
+	key, _ := json.Marshal(name)
+	return string(key)
+}
+
+func docComment(description string, depth int) string {
+	if len(description) < 1 {
+		return ""
+	}
+
+	description = strings.ReplaceAll(description, "*/", "*\\/")
+	lines := strings.Split(strings.TrimSpace(description), "\n")
+
+	if len(lines) == 1 {
+		return fmt.Sprintf("%s/** %s */\n", indent(depth), lines[0])
+	}
+
+	var comment strings.Builder
+	comment.WriteString(indent(depth) + "/**\n")
+
+	for _, line := range lines {
+		comment.WriteString(strings.TrimRight(fmt.Sprintf("%s * %s", indent(depth), line), " ") + "\n")
+	}
+
+	comment.WriteString(indent(depth) + " */\n")
+
+	return comment.String()
+}
+
+func withNull(expression string, nullable bool) string {
+	if nullable {
+		return expression + " | null"
+	}
+
+	return expression
+}
+
+func withNullable(expression string, nullable bool) string {
+	if nullable {
+		return expression + ".nullable()"
+	}
+
+	return expression
+}
+
+// optionalMark returns the mark of the optional properties, the nullable properties can also be absent
+func optionalMark(nullable bool) string {
+	if nullable {
+		return "?"
+	}
+
+	return ""
+}
+
+func withOptional(expression string, nullable bool) string {
+	if nullable {
+		return expression + ".optional()"
+	}
+
+	return expression
+}
+
+func indent(depth int) string {
+	return strings.Repeat(indentation, depth)
+}
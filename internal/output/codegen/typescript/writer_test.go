@@ -0,0 +1,161 @@
+package typescript_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/codegen/typescript"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+)
+
+const lifecycleDefinition = `
+version: "1.0"
+name: super-cool-service
+
+events:
+  published:
+    CAKE_BURNED:
+      visibility: public
+      description: Evento disparado quando o bolo é queimado
+      attributes:
+        type: object
+        properties:
+          cake:
+            $ref: '#/types/Cake'
+          burned-at:
+            type: string
+            format: date-time
+            value: "2022-10-20T10:00:00Z"
+      entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+            format: uuid
+            value: 41af6672-5b3a-4d5c-9be1-7c93dc1614e1
+
+types:
+  CakeShape:
+    description: Enum dos formatos de bolo suportado
+    type: string
+    nullable: true
+    enum:
+      - squad
+      - circle
+    value: circle
+
+  Cake:
+    description: |-
+      Representa um bolo
+      com camadas
+    type: object
+    properties:
+      shape:
+        $ref: '#/types/CakeShape'
+      layers:
+        type: array
+        description: Camadas do bolo
+        items:
+          type: integer
+          enum: [1, 2]
+          value: 1
+      category:
+        $ref: '#/types/Category'
+
+  Category:
+    type: object
+    properties:
+      parent:
+        $ref: '#/types/Category'
+        nullable: true`
+
+func TestShouldWriteTypeScriptTypes(t *testing.T) {
+	expected := `// Code generated by lifecycledoc. DO NOT EDIT.
+
+/** Enum dos formatos de bolo suportado */
+export type CakeShape = "squad" | "circle" | null;
+
+/**
+ * Representa um bolo
+ * com camadas
+ */
+export interface Cake {
+  shape: CakeShape;
+  /** Camadas do bolo */
+  layers: (1 | 2)[];
+  category: Category;
+}
+
+export interface Category {
+  parent?: Category | null;
+}
+
+/** Evento disparado quando o bolo é queimado */
+export interface CakeBurnedEvent {
+  attributes: {
+    cake: Cake;
+    "burned-at": string;
+  };
+  entities: {
+    cakeId: string;
+  };
+}
+`
+
+	if code := writeCode(t, typescript.NewWriter(false)); code != expected {
+		t.Errorf("expected '%s', received '%s'", expected, code)
+	}
+}
+
+func TestShouldWriteZodSchemas(t *testing.T) {
+	expected := `
+export const CakeShapeSchema: z.ZodType<CakeShape> = z.enum(["squad", "circle"]).nullable();
+
+export const CakeSchema: z.ZodType<Cake> = z.object({
+  shape: z.lazy(() => CakeShapeSchema),
+  layers: z.array(z.union([z.literal(1), z.literal(2)])),
+  category: z.lazy(() => CategorySchema),
+});
+
+export const CategorySchema: z.ZodType<Category> = z.object({
+  parent: z.lazy(() => CategorySchema).nullable().optional(),
+});
+
+export const CakeBurnedEventSchema: z.ZodType<CakeBurnedEvent> = z.object({
+  attributes: z.object({
+    cake: z.lazy(() => CakeSchema),
+    "burned-at": z.string().datetime(),
+  }),
+  entities: z.object({
+    cakeId: z.string().uuid(),
+  }),
+});
+`
+
+	code := writeCode(t, typescript.NewWriter(true))
+
+	if !strings.HasPrefix(code, "// Code generated by lifecycledoc. DO NOT EDIT.\n\nimport { z } from \"zod\";\n") {
+		t.Errorf("expected zod import, received '%s'", code)
+	}
+
+	if !strings.HasSuffix(code, expected) {
+		t.Errorf("expected '%s' zod schemas, received '%s'", expected, code)
+	}
+}
+
+func writeCode(t *testing.T, writer *typescript.Writer) string {
+	t.Helper()
+
+	schemaResolver := schema.NewBasicResolver()
+	if err := yaml.NewDecoder().Decode(strings.NewReader(lifecycleDefinition), schemaResolver); err != nil {
+		t.Fatal(err)
+	}
+
+	var code strings.Builder
+	if err := writer.Write(&code, schemaResolver); err != nil {
+		t.Fatal(err)
+	}
+
+	return code.String()
+}
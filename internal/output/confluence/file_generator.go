@@ -0,0 +1,83 @@
+package confluence
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+)
+
+// FileGenerator writes the rendered Confluence storage body of each page to a directory instead of publishing it
+type FileGenerator struct {
+	outDir         string
+	templateWriter *TemplateWriter
+}
+
+func NewFileGenerator(outDir string, templateRetriver TemplateRetriver) *FileGenerator {
+	return &FileGenerator{
+		outDir:         outDir,
+		templateWriter: NewTemplateWriter(templateRetriver),
+	}
+}
+
+// Generate writes one file per Confluence page and returns the written file paths in declaration order
+func (f *FileGenerator) Generate(schemaResolver schema.Resolver) ([]string, error) {
+	confluence, err := schemaResolver.GetConfluence()
+	if err != nil {
+		return nil, err
+	}
+
+	body := &strings.Builder{}
+	if err := f.templateWriter.Write(body, schemaResolver); err != nil {
+		return nil, fmt.Errorf("can't generate Confluence Content Body: %w", err)
+	}
+
+	if err := os.MkdirAll(f.outDir, 0755); err != nil {
+		return nil, fmt.Errorf("can't create output directory '%s': %w", f.outDir, err)
+	}
+
+	var (
+		pages = confluence.Pages()
+		paths = make([]string, len(pages))
+		// titles by file name, the titles with the same file name would overwrite each other
+		titles = make(map[string]string, len(pages))
+	)
+
+	for i := range pages {
+		fileName := pageTitleToFileName(pages[i].Title())
+
+		if title, exists := titles[fileName]; exists {
+			return nil, fmt.Errorf(`pages "%s" and "%s" have the same file name '%s'`, title, pages[i].Title(), fileName)
+		}
+
+		titles[fileName] = pages[i].Title()
+		paths[i] = filepath.Join(f.outDir, fileName)
+	}
+
+	for i := range pages {
+		if err := os.WriteFile(paths[i], []byte(body.String()), 0644); err != nil {
+			return nil, fmt.Errorf(`can't write page "%s" to file '%s': %w`, pages[i].Title(), paths[i], err)
+		}
+	}
+
+	return paths, nil
+}
+
+func pageTitleToFileName(title string) string {
+	fileName := strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+
+		if r < ' ' {
+			return '_'
+		}
+
+		return r
+	}, title)
+
+	return fmt.Sprintf("%s.html", fileName)
+}
@@ -0,0 +1,105 @@
+package confluence_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/confluence"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+)
+
+func TestShouldWriteOneFilePerPage(t *testing.T) {
+	input := strings.NewReader(`
+version: "1.0"
+name: super-cool-service
+
+confluence:
+  pages:
+    - spaceKey: "SPACEKEY"
+      ancestorId: "123456789"
+      title: "Eventos: bolos"
+    - spaceKey: "OTHERSPACE"
+      ancestorId: "987654321"
+
+events:
+  consumed:
+    CAKE_PURCHASED:
+      description: Usado para inciar o processo de fazer o bolo`)
+
+	schemaResolver := schema.NewBasicResolver()
+	if err := yaml.NewDecoder().Decode(input, schemaResolver); err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := t.TempDir()
+	generator := confluence.NewFileGenerator(outDir, confluence.TemplateRetriverFunc(newConsumedEventsTemplateMock))
+
+	paths, err := generator.Generate(schemaResolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPaths := []string{
+		filepath.Join(outDir, "Eventos_ bolos.html"),
+		filepath.Join(outDir, "Life Cycle Events_ super-cool-service.html"),
+	}
+
+	if len(paths) != len(expectedPaths) {
+		t.Fatalf("expected '%d' files, received '%d'", len(expectedPaths), len(paths))
+	}
+
+	for i := range expectedPaths {
+		if paths[i] != expectedPaths[i] {
+			t.Errorf("expected '%s' file, received '%s'", expectedPaths[i], paths[i])
+		}
+
+		content, err := os.ReadFile(paths[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertStringWithNewLinesAndIdentation(t, `CAKE_PURCHASED|Usado para inciar o processo de fazer o bolo|||`, string(content))
+	}
+}
+
+func TestShouldReportPagesWithTheSameFileName(t *testing.T) {
+	input := strings.NewReader(`
+version: "1.0"
+name: super-cool-service
+
+confluence:
+  pages:
+    - spaceKey: "SPACEKEY"
+      ancestorId: "123456789"
+      title: "Eventos: bolos"
+    - spaceKey: "OTHERSPACE"
+      ancestorId: "987654321"
+      title: "Eventos/ bolos"
+
+events:
+  consumed:
+    CAKE_PURCHASED:
+      description: Usado para inciar o processo de fazer o bolo`)
+
+	schemaResolver := schema.NewBasicResolver()
+	if err := yaml.NewDecoder().Decode(input, schemaResolver); err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := t.TempDir()
+	generator := confluence.NewFileGenerator(outDir, confluence.TemplateRetriverFunc(newConsumedEventsTemplateMock))
+
+	_, err := generator.Generate(schemaResolver)
+
+	expected := `pages "Eventos: bolos" and "Eventos/ bolos" have the same file name 'Eventos_ bolos.html'`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected error '%s', received '%v'", expected, err)
+	}
+
+	if entries, _ := os.ReadDir(outDir); len(entries) > 0 {
+		t.Errorf("expected no written files, received '%d'", len(entries))
+	}
+}
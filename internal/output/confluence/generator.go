@@ -10,6 +10,7 @@ import (
 	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/client/confluence"
 	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
 	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/textdiff"
 	goconfluence "github.com/virtomize/confluence-go-api"
 )
 
@@ -22,9 +23,30 @@ type Generator struct {
 	templateWriter *TemplateWriter
 }
 
+type GenerateAction string
+
+const (
+	GenerateActionCreated   GenerateAction = "created"
+	GenerateActionUpdated   GenerateAction = "updated"
+	GenerateActionUnchanged GenerateAction = "unchanged"
+	// GenerateActionWritten is the action of the pages written to files in the dry-run mode, without Confluence content
+	GenerateActionWritten GenerateAction = "written"
+)
+
 type GenerateResult struct {
+	Page    *types.ConfluencePage
 	Content *goconfluence.Content
-	Err     error
+	Action  GenerateAction
+	// File is the path of the file written in the dry-run mode
+	File string
+	Err  error
+}
+
+type DiffResult struct {
+	Page *types.ConfluencePage
+	// Diff stores the changes in unified format. Empty when the page is up to date
+	Diff string
+	Err  error
 }
 
 func NewGenerator(client *confluence.Client, templateRetriver TemplateRetriver) *Generator {
@@ -45,11 +67,37 @@ func (g *Generator) Generate(ctx context.Context, schemaResolver schema.Resolver
 		return nil, fmt.Errorf("can't generate Confluence Content Body: %w", err)
 	}
 
-	var (
-		pages = confluence.Pages()
+	resultChan := processPages(confluence.Pages(), func(page *types.ConfluencePage) GenerateResult {
+		return g.createOrUpdatePage(ctx, page, contentBody)
+	})
+
+	return resultChan, nil
+}
+
+// Diff compares the rendered content of each page with its current content in Confluence
+func (g *Generator) Diff(ctx context.Context, schemaResolver schema.Resolver) (<-chan DiffResult, error) {
+	confluence, err := schemaResolver.GetConfluence()
+	if err != nil {
+		return nil, err
+	}
 
+	contentBody, err := g.generateContentBody(ctx, schemaResolver)
+	if err != nil {
+		return nil, fmt.Errorf("can't generate Confluence Content Body: %w", err)
+	}
+
+	resultChan := processPages(confluence.Pages(), func(page *types.ConfluencePage) DiffResult {
+		return g.diffPage(ctx, page, contentBody)
+	})
+
+	return resultChan, nil
+}
+
+// processPages calls "process" for each page using a limited number of goroutines
+func processPages[T any](pages []*types.ConfluencePage, process func(page *types.ConfluencePage) T) <-chan T {
+	var (
 		inputChan  = make(chan *types.ConfluencePage, len(pages))
-		resultChan = make(chan GenerateResult, len(pages))
+		resultChan = make(chan T, len(pages))
 
 		wg sync.WaitGroup
 	)
@@ -63,7 +111,7 @@ func (g *Generator) Generate(ctx context.Context, schemaResolver schema.Resolver
 			defer wg.Done()
 
 			for page := range inputChan {
-				g.createOrUpdatePage(ctx, page, contentBody, resultChan)
+				resultChan <- process(page)
 			}
 		}()
 	}
@@ -77,21 +125,21 @@ func (g *Generator) Generate(ctx context.Context, schemaResolver schema.Resolver
 		inputChan <- pages[i]
 	}
 
-	return resultChan, nil
+	return resultChan
 }
 
 func (g *Generator) createOrUpdatePage(
 	ctx context.Context,
 	page *types.ConfluencePage,
 	contentBody *goconfluence.Body,
-	resultChan chan<- GenerateResult,
-) {
+) GenerateResult {
 	limitedCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
-	content, err := g.createOrUpdate(limitedCtx, page, contentBody)
+	content, action, err := g.createOrUpdate(limitedCtx, page, contentBody)
 	if err != nil {
-		resultChan <- GenerateResult{
+		return GenerateResult{
+			Page: page,
 			Err: fmt.Errorf(
 				`can't create or update page "%s" in space "%s" with ancestor "%s": %w`,
 				page.Title(),
@@ -100,20 +148,57 @@ func (g *Generator) createOrUpdatePage(
 				err,
 			),
 		}
-
-		return
 	}
 
 	if content == nil {
-		resultChan <- GenerateResult{
-			Err: fmt.Errorf("confluence API did not return a body with the generated documentation information"),
+		return GenerateResult{
+			Page: page,
+			Err:  fmt.Errorf("confluence API did not return a body with the generated documentation information"),
 		}
-
-		return
 	}
 
-	resultChan <- GenerateResult{
+	return GenerateResult{
+		Page:    page,
 		Content: content,
+		Action:  action,
+	}
+}
+
+func (g *Generator) diffPage(
+	ctx context.Context,
+	page *types.ConfluencePage,
+	contentBody *goconfluence.Body,
+) DiffResult {
+	limitedCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	current, err := g.findCurrentContent(limitedCtx, g.generateContent(page, contentBody), 0)
+	if err != nil {
+		return DiffResult{
+			Page: page,
+			Err: fmt.Errorf(
+				`can't retrieve page "%s" in space "%s" with ancestor "%s": %w`,
+				page.Title(),
+				page.SpaceKey(),
+				page.AncestorID(),
+				err,
+			),
+		}
+	}
+
+	var currentBody string
+	if current != nil {
+		currentBody = current.Body.Storage.Value
+	}
+
+	return DiffResult{
+		Page: page,
+		Diff: textdiff.Unified(
+			fmt.Sprintf("confluence/%s/%s", page.SpaceKey(), page.Title()),
+			fmt.Sprintf("lifecycledoc/%s/%s", page.SpaceKey(), page.Title()),
+			normalizeStorage(currentBody),
+			normalizeStorage(contentBody.Storage.Value),
+		),
 	}
 }
 
@@ -135,19 +220,37 @@ func (g *Generator) createOrUpdate(
 	ctx context.Context,
 	page *types.ConfluencePage,
 	body *goconfluence.Body,
-) (*goconfluence.Content, error) {
+) (*goconfluence.Content, GenerateAction, error) {
 	content := g.generateContent(page, body)
 
-	exists, err := g.findAndUpdateCurrentContent(ctx, content, 0)
+	current, err := g.findCurrentContent(ctx, content, 0)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	if current == nil {
+		created, err := g.client.CreateContent(ctx, content)
+		return created, GenerateActionCreated, err
 	}
 
-	if !exists {
-		return g.client.CreateContent(ctx, content)
-	} else {
-		return g.client.UpdateContent(ctx, content)
+	// Avoid bumping the page version (and notifying the watchers) when nothing changed
+	if storageEquals(current.Body.Storage.Value, body.Storage.Value) {
+		if current.Links == nil {
+			current.Links = &goconfluence.Links{}
+		}
+
+		if len(current.Links.Base) < 1 {
+			current.Links.Base = g.client.LinksBase()
+		}
+
+		return current, GenerateActionUnchanged, nil
 	}
+
+	content.ID = current.ID
+	content.Version.Number = current.Version.Number + 1
+
+	content, err = g.client.UpdateContent(ctx, content)
+	return content, GenerateActionUpdated, err
 }
 
 func (g *Generator) generateContent(
@@ -187,28 +290,26 @@ func (g *Generator) generateContent(
 	return content
 }
 
-func (g *Generator) findAndUpdateCurrentContent(ctx context.Context, content *goconfluence.Content, start int) (bool, error) {
-	result, err := g.client.FindContent(ctx, content, start)
+// findCurrentContent returns the published content with the same title and ancestor or nil when it doesn't exist
+func (g *Generator) findCurrentContent(ctx context.Context, content *goconfluence.Content, start int) (*goconfluence.Content, error) {
+	result, err := g.client.FindContentWithBody(ctx, content, start)
 	if err != nil {
-		return false, fmt.Errorf("can't search current documentation: %w", err)
+		return nil, fmt.Errorf("can't search current documentation: %w", err)
 	}
 
 	for i := range result.Results {
 		if result.Results[i].Title == content.Title {
 			for j := range result.Results[i].Ancestors {
 				if result.Results[i].Ancestors[j].ID == content.Ancestors[0].ID {
-					content.ID = result.Results[i].ID
-					content.Version.Number = result.Results[i].Version.Number + 1
-
-					return true, nil
+					return &result.Results[i], nil
 				}
 			}
 		}
 	}
 
 	if result.Size > result.Limit {
-		return g.findAndUpdateCurrentContent(ctx, content, start+result.Limit)
+		return g.findCurrentContent(ctx, content, start+result.Limit)
 	}
 
-	return false, nil
+	return nil, nil
 }
@@ -0,0 +1,199 @@
+package confluence_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/confluence"
+	confluenceRest "github.com/madeiramadeirabr/action-lifecycledoc/pkg/client/confluence"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+	goconfluence "github.com/virtomize/confluence-go-api"
+)
+
+func TestShouldDiffRenderedPageWithCurrentPage(t *testing.T) {
+	testCases := []struct {
+		name          string
+		currentBody   string
+		expectChanges bool
+	}{
+		{
+			name:          "should not report changes when only the formatting differs",
+			currentBody:   "<p>\n  CAKE_PURCHASED</p><p></p>",
+			expectChanges: false,
+		},
+		{
+			name:          "should report changes",
+			currentBody:   "<p>CAKE_BURNED</p><p />",
+			expectChanges: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := newConfluenceServerStub(t, testCase.currentBody)
+			defer server.Close()
+
+			generator := confluence.NewGenerator(
+				confluenceRest.NewClient(server.Client(), server.URL, ""),
+				confluence.TemplateRetriverFunc(func() string {
+					return "{{range .ConsumedEvents}}<p>{{.Name}}</p>{{end}}<p />"
+				}),
+			)
+
+			resultChan, err := generator.Diff(context.Background(), newConsumedEventSchemaResolver(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for result := range resultChan {
+				if result.Err != nil {
+					t.Fatal(result.Err)
+				}
+
+				if hasChanges := len(result.Diff) > 0; hasChanges != testCase.expectChanges {
+					t.Errorf("expected changes '%v', received '%v': %s", testCase.expectChanges, hasChanges, result.Diff)
+				}
+			}
+		})
+	}
+}
+
+func TestShouldSkipUpdateWhenContentHasNotChanged(t *testing.T) {
+	testCases := []struct {
+		name           string
+		currentBody    string
+		expectedAction confluence.GenerateAction
+	}{
+		{
+			name:           "should not update unchanged page",
+			currentBody:    "<p>CAKE_PURCHASED</p><p></p>",
+			expectedAction: confluence.GenerateActionUnchanged,
+		},
+		{
+			name:           "should update changed page",
+			currentBody:    "<p>CAKE_BURNED</p><p />",
+			expectedAction: confluence.GenerateActionUpdated,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := newConfluenceServerStub(t, testCase.currentBody)
+			defer server.Close()
+
+			generator := confluence.NewGenerator(
+				confluenceRest.NewClient(server.Client(), server.URL, ""),
+				confluence.TemplateRetriverFunc(func() string {
+					return "{{range .ConsumedEvents}}<p>{{.Name}}</p>{{end}}<p />"
+				}),
+			)
+
+			resultChan, err := generator.Generate(context.Background(), newConsumedEventSchemaResolver(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for result := range resultChan {
+				if result.Err != nil {
+					t.Fatal(result.Err)
+				}
+
+				if result.Action != testCase.expectedAction {
+					t.Errorf("expected '%s' action, received '%s'", testCase.expectedAction, result.Action)
+				}
+
+				if result.Content.Links == nil || len(result.Content.Links.Base) < 1 {
+					t.Errorf("expected links base in result content, received '%#v'", result.Content.Links)
+				}
+			}
+		})
+	}
+}
+
+func newConsumedEventSchemaResolver(t *testing.T) schema.Resolver {
+	t.Helper()
+
+	input := strings.NewReader(`
+version: "1.0"
+name: super-cool-service
+
+confluence:
+  pages:
+    - spaceKey: "SPACEKEY"
+      ancestorId: "123456789"
+      title: Titulo
+
+events:
+  consumed:
+    CAKE_PURCHASED:
+      description: Usado para inciar o processo de fazer o bolo`)
+
+	schemaResolver := schema.NewBasicResolver()
+	if err := yaml.NewDecoder().Decode(input, schemaResolver); err != nil {
+		t.Fatal(err)
+	}
+
+	return schemaResolver
+}
+
+func newConfluenceServerStub(t *testing.T, currentBody string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodPut && r.URL.Path == "/wiki/rest/api/content/42" {
+			var content goconfluence.Content
+			if err := json.NewDecoder(r.Body).Decode(&content); err != nil {
+				t.Error(err)
+			}
+
+			content.Links = &goconfluence.Links{
+				Base:   "https://example.atlassian.net/wiki",
+				TinyUI: "/x/42",
+			}
+
+			if err := json.NewEncoder(w).Encode(content); err != nil {
+				t.Error(err)
+			}
+
+			return
+		}
+
+		if r.Method != http.MethodGet || r.URL.Path != "/wiki/rest/api/content" {
+			t.Errorf("unexpected request '%s %s'", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		result := goconfluence.ContentSearch{
+			Results: []goconfluence.Content{
+				{
+					ID:    "42",
+					Title: r.URL.Query().Get("title"),
+					Ancestors: []goconfluence.Ancestor{
+						{ID: "123456789"},
+					},
+					Body: goconfluence.Body{
+						Storage: goconfluence.Storage{
+							Value:          currentBody,
+							Representation: "storage",
+						},
+					},
+					Version: &goconfluence.Version{Number: 3},
+				},
+			},
+			Size:  1,
+			Limit: 25,
+		}
+
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			t.Error(err)
+		}
+	}))
+}
@@ -0,0 +1,60 @@
+package confluence
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	betweenTagsRegexp   = regexp.MustCompile(`>\s*<`)
+	lineBreakRegexp     = regexp.MustCompile(`>[ \t]*\r?\n\s*|\s*\r?\n[ \t]*<`)
+	emptyElementRegexp  = regexp.MustCompile(`<([A-Za-z][\w:-]*)((?:\s[^<>]*?)?)\s*>\s*</([A-Za-z][\w:-]*)>`)
+	selfClosingRegexp   = regexp.MustCompile(`\s*/>`)
+	multipleSpaceRegexp = regexp.MustCompile(`\s+`)
+)
+
+// normalizeStorage splits a storage format document in comparable lines, ignoring the formatting
+// differences introduced by Confluence when the document is stored
+func normalizeStorage(storage string) []string {
+	storage = emptyElementRegexp.ReplaceAllStringFunc(storage, func(element string) string {
+		matches := emptyElementRegexp.FindStringSubmatch(element)
+		if matches[1] != matches[3] {
+			return element
+		}
+
+		return "<" + matches[1] + matches[2] + " />"
+	})
+
+	storage = selfClosingRegexp.ReplaceAllString(storage, " />")
+	storage = lineBreakRegexp.ReplaceAllStringFunc(storage, func(lineBreak string) string {
+		return strings.TrimSpace(lineBreak)
+	})
+	storage = betweenTagsRegexp.ReplaceAllString(storage, ">\n<")
+
+	var lines []string
+	for _, line := range strings.Split(storage, "\n") {
+		line = multipleSpaceRegexp.ReplaceAllString(strings.TrimSpace(line), " ")
+
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// storageEquals reports whether both storage format documents have the same normalized content
+func storageEquals(a, b string) bool {
+	linesA, linesB := normalizeStorage(a), normalizeStorage(b)
+	if len(linesA) != len(linesB) {
+		return false
+	}
+
+	for i := range linesA {
+		if linesA[i] != linesB[i] {
+			return false
+		}
+	}
+
+	return true
+}
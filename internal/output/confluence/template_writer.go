@@ -218,6 +218,21 @@ func (t *TemplateWriter) typeDescriberToTypeOutput(typeDescriber types.TypeDescr
 
 func (t *TemplateWriter) typeDescriberToExample(inRootLevel bool, typeDescriber types.TypeDescriber) (interface{}, error) {
 	switch typeDescriber := typeDescriber.(type) {
+	case *types.RecursiveReference:
+		// The referenced definition contains the reference, so it is not expanded again
+		var example interface{}
+
+		switch typeDescriber.Type() {
+		case types.ObjectType:
+			example = jsonc.MapSlice{}
+		case types.ArrayType:
+			example = []interface{}{}
+		}
+
+		return jsonc.NewCommentValue(
+			t.createComment(typeDescriber, " (recursive)"),
+			example,
+		), nil
 	case types.ScalarDescriber:
 		if !inRootLevel {
 			var typeModifier string
@@ -288,7 +303,13 @@ func (t *TemplateWriter) createComment(typeDescriber types.TypeDescriber, typeMo
 	refereceType, is := typeDescriber.(types.ReferenceDescriber)
 	if is {
 		identifier = refereceType.Reference()
-		typeModifier = ""
+
+		// Only the recursive references have modifiers, the other references are described by the referenced type
+		if recursiveReference, isRecursive := typeDescriber.(*types.RecursiveReference); isRecursive {
+			identifier = recursiveReference.Target().Name()
+		} else {
+			typeModifier = ""
+		}
 	} else {
 		identifier = typeDescriber.Type()
 	}
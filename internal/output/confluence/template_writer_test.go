@@ -131,6 +131,40 @@ types:
 	})
 }
 
+func TestShouldWriteRecursiveTypes(t *testing.T) {
+	input := strings.NewReader(`
+version: "1.0"
+name: super-cool-service
+
+types:
+  Category:
+    description: Categoria do bolo
+    type: object
+    properties:
+      name:
+        type: string
+        value: Bolos
+      parent:
+        $ref: '#/types/Category'
+        description: Categoria pai
+        nullable: true
+      children:
+        type: array
+        items:
+          $ref: '#/types/Category'`)
+
+	schemaResolver := schema.NewBasicResolver()
+	decoder := yaml.NewDecoder()
+
+	if err := decoder.Decode(input, schemaResolver); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"name": "Bolos", // string"parent": {}, // Category (recursive)|null: Categoria pai"children": [{} // Category (recursive): Categoria do bolo] // array}|||`
+
+	assertTempleWriterOutput(t, confluence.TemplateRetriverFunc(newTypesTemplateMock), schemaResolver, expected)
+}
+
 func newTypesTemplateMock() string {
 	return "{{range .Types}}{{.Example}}|||{{end}}"
 }
@@ -0,0 +1,242 @@
+// jsonschema package converts the resolved lifecycle definitions to JSON Schema
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/codegen"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/jsonc"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+)
+
+// nullType is the JSON Schema type of null values
+const nullType = "null"
+
+// integerRanges are the minimum and maximum values of the integer formats, which have no JSON Schema format
+var integerRanges = map[string][2]interface{}{
+	"int8":   {math.MinInt8, math.MaxInt8},
+	"int16":  {math.MinInt16, math.MaxInt16},
+	"int32":  {math.MinInt32, math.MaxInt32},
+	"int64":  {int64(math.MinInt64), int64(math.MaxInt64)},
+	"uint8":  {0, math.MaxUint8},
+	"uint16": {0, math.MaxUint16},
+	"uint32": {0, uint32(math.MaxUint32)},
+	"uint64": {0, uint64(math.MaxUint64)},
+}
+
+/*
+Converter converts the resolved definitions to JSON Schema. The references to declared types are converted to
+"$ref" keywords with the reference prefix, like "#/$defs/" or "#/components/schemas/", followed by the type name
+*/
+type Converter struct {
+	referencePrefix string
+
+	declaredTypes []types.TypeDescriber
+	// references identifies the references to declared types, which are converted to "$ref" keywords
+	references *codegen.DeclaredTypes
+}
+
+func NewConverter(referencePrefix string, declaredTypes []types.TypeDescriber) *Converter {
+	return &Converter{
+		referencePrefix: referencePrefix,
+		declaredTypes:   declaredTypes,
+		references:      codegen.NewDeclaredTypes(declaredTypes),
+	}
+}
+
+// Definitions returns the schemas of the declared types by name, in declaration order
+func (c *Converter) Definitions() (jsonc.MapSlice, error) {
+	definitions := make(jsonc.MapSlice, 0, len(c.declaredTypes))
+
+	for i := range c.declaredTypes {
+		definition, err := c.Schema(c.declaredTypes[i])
+		if err != nil {
+			return nil, err
+		}
+
+		definitions = append(definitions, jsonc.MapItem{
+			Key:   c.declaredTypes[i].Name(),
+			Value: definition,
+		})
+	}
+
+	return definitions, nil
+}
+
+// PublishedEventSchema returns the schema of the message of the published event, with its attributes and entities
+func (c *Converter) PublishedEventSchema(event *types.PublishedEvent) (jsonc.MapSlice, error) {
+	attributes, err := c.Schema(event.Attributes())
+	if err != nil {
+		return nil, err
+	}
+
+	entities, err := c.Schema(event.Entities())
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonc.MapSlice{
+		{Key: "type", Value: "object"},
+		{
+			Key: "properties",
+			Value: jsonc.MapSlice{
+				{Key: "attributes", Value: attributes},
+				{Key: "entities", Value: entities},
+			},
+		},
+		{Key: "required", Value: []interface{}{"attributes", "entities"}},
+	}, nil
+}
+
+// Schema returns the JSON Schema of the resolved definition
+func (c *Converter) Schema(typeDescriber types.TypeDescriber) (jsonc.MapSlice, error) {
+	name, isReference, err := c.references.Reference(typeDescriber)
+	if err != nil {
+		return nil, err
+	}
+
+	if isReference {
+		return c.referenceSchema(name, typeDescriber), nil
+	}
+
+	// The references to undeclared types, like nested definitions, are inlined
+	switch typeDescriber := typeDescriber.(type) {
+	case types.ScalarDescriber:
+		return c.scalarSchema(typeDescriber), nil
+	case types.ArrayDescriber:
+		return c.arraySchema(typeDescriber)
+	case types.ObjectDescriber:
+		return c.objectSchema(typeDescriber)
+	}
+
+	return nil, fmt.Errorf("type '%T' of definition '%s' is not supported", typeDescriber, typeDescriber.Path())
+}
+
+func (c *Converter) scalarSchema(scalarType types.ScalarDescriber) jsonc.MapSlice {
+	schema := c.typeSchema(scalarType)
+
+	if scalarType.HasFormat() {
+		schema = append(schema, jsonc.MapItem{Key: "format", Value: scalarType.Format()})
+
+		if valueRange, exists := integerRanges[scalarType.Format()]; exists && scalarType.Type() == types.ScalarIntegerType {
+			schema = append(
+				schema,
+				jsonc.MapItem{Key: "minimum", Value: valueRange[0]},
+				jsonc.MapItem{Key: "maximum", Value: valueRange[1]},
+			)
+		}
+	}
+
+	if scalarType.HasEnum() {
+		enum := scalarType.Enum()
+
+		// The null value must be in the enum to be accepted
+		if scalarType.Nullable() && !containsNull(enum) {
+			enum = append(append([]interface{}{}, enum...), nil)
+		}
+
+		schema = append(schema, jsonc.MapItem{Key: "enum", Value: enum})
+	}
+
+	if value := scalarType.Value(); value != nil {
+		schema = append(schema, jsonc.MapItem{Key: "examples", Value: []interface{}{value}})
+	}
+
+	return schema
+}
+
+func (c *Converter) arraySchema(arrayType types.ArrayDescriber) (jsonc.MapSlice, error) {
+	items, err := c.Schema(arrayType.Items())
+	if err != nil {
+		return nil, err
+	}
+
+	return append(c.typeSchema(arrayType), jsonc.MapItem{Key: "items", Value: items}), nil
+}
+
+func (c *Converter) objectSchema(objectType types.ObjectDescriber) (jsonc.MapSlice, error) {
+	var (
+		propertiesTypes = objectType.Properties()
+		properties      = make(jsonc.MapSlice, 0, len(propertiesTypes))
+		// The nullable properties accept the absence of value
+		required []interface{}
+	)
+
+	for i := range propertiesTypes {
+		property, err := c.Schema(propertiesTypes[i])
+		if err != nil {
+			return nil, err
+		}
+
+		properties = append(properties, jsonc.MapItem{
+			Key:   propertiesTypes[i].Name(),
+			Value: property,
+		})
+
+		if !propertiesTypes[i].Nullable() {
+			required = append(required, propertiesTypes[i].Name())
+		}
+	}
+
+	schema := append(c.typeSchema(objectType), jsonc.MapItem{Key: "properties", Value: properties})
+	if len(required) > 0 {
+		schema = append(schema, jsonc.MapItem{Key: "required", Value: required})
+	}
+
+	return schema, nil
+}
+
+// typeSchema returns the schema with the type and description keywords
+func (c *Converter) typeSchema(typeDescriber types.TypeDescriber) jsonc.MapSlice {
+	var typeKeyword interface{} = typeDescriber.Type()
+	if typeDescriber.Nullable() {
+		typeKeyword = []interface{}{typeDescriber.Type(), nullType}
+	}
+
+	schema := jsonc.MapSlice{{Key: "type", Value: typeKeyword}}
+	return appendDescription(schema, typeDescriber)
+}
+
+/*
+referenceSchema returns the reference to the declared type. The nullable references are declared as one of the
+reference or null, since some JSON Schema versions ignore the keywords declared with "$ref". The description is
+only declared when the reference overrides the description of the declared type
+*/
+func (c *Converter) referenceSchema(name string, typeDescriber types.TypeDescriber) jsonc.MapSlice {
+	schema := jsonc.MapSlice{{Key: "$ref", Value: c.referencePrefix + name}}
+
+	if typeDescriber.Nullable() {
+		schema = jsonc.MapSlice{{
+			Key: "oneOf",
+			Value: []interface{}{
+				schema,
+				jsonc.MapSlice{{Key: "type", Value: nullType}},
+			},
+		}}
+	}
+
+	if description := c.references.Description(typeDescriber); len(description) > 0 {
+		schema = append(schema, jsonc.MapItem{Key: "description", Value: description})
+	}
+
+	return schema
+}
+
+func appendDescription(schema jsonc.MapSlice, typeDescriber types.TypeDescriber) jsonc.MapSlice {
+	if description := typeDescriber.Description(); len(description) > 0 {
+		schema = append(schema, jsonc.MapItem{Key: "description", Value: description})
+	}
+
+	return schema
+}
+
+func containsNull(values []interface{}) bool {
+	for i := range values {
+		if values[i] == nil {
+			return true
+		}
+	}
+
+	return false
+}
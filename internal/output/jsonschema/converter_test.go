@@ -0,0 +1,80 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/jsonschema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+)
+
+func TestShouldConvertTypesToJSONSchema(t *testing.T) {
+	schemaResolver := schema.NewBasicResolver()
+	err := yaml.NewDecoder().Decode(strings.NewReader(`
+version: "1.0"
+name: super-cool-service
+
+types:
+  CakeShape:
+    description: Enum dos formatos de bolo suportado
+    type: string
+    enum:
+      - squad
+      - circle
+    nullable: true
+    value: circle
+
+  Cake:
+    type: object
+    properties:
+      id:
+        type: string
+        format: uuid
+        value: 41af6672-5b3a-4d5c-9be1-7c93dc1614e1
+      shape:
+        $ref: '#/types/CakeShape'
+      weight:
+        type: integer
+        format: uint8
+        value: 5
+      layers:
+        type: array
+        description: Camadas do bolo
+        items:
+          $ref: '#/types/Cake'
+          description: Camada`), schemaResolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	declaredTypes, err := schemaResolver.GetTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	definitions, err := jsonschema.NewConverter("#/$defs/", declaredTypes).Definitions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	definitionsJSON, err := json.Marshal(definitions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{` +
+		`"CakeShape":{"type":["string","null"],"description":"Enum dos formatos de bolo suportado","enum":["squad","circle",null],"examples":["circle"]},` +
+		`"Cake":{"type":"object","properties":{` +
+		`"id":{"type":"string","format":"uuid","examples":["41af6672-5b3a-4d5c-9be1-7c93dc1614e1"]},` +
+		`"shape":{"$ref":"#/$defs/CakeShape"},` +
+		`"weight":{"type":"integer","format":"uint8","minimum":0,"maximum":255,"examples":[5]},` +
+		`"layers":{"type":"array","description":"Camadas do bolo","items":{"$ref":"#/$defs/Cake","description":"Camada"}}` +
+		`},"required":["id","shape","weight","layers"]}` +
+		`}`
+
+	if string(definitionsJSON) != expected {
+		t.Errorf("expected '%s', received '%s'", expected, definitionsJSON)
+	}
+}
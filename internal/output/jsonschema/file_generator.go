@@ -0,0 +1,107 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/codegen"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/jsonc"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+)
+
+const (
+	draft202012     = "https://json-schema.org/draft/2020-12/schema"
+	definitionsPath = "#/$defs/"
+)
+
+// FileGenerator writes a Draft 2020-12 JSON Schema document for each published event to a directory
+type FileGenerator struct {
+	outDir string
+}
+
+func NewFileGenerator(outDir string) *FileGenerator {
+	return &FileGenerator{
+		outDir: outDir,
+	}
+}
+
+/*
+Generate writes one file per published event and returns the written file paths in declaration order. The declared
+types are written in the "$defs" of every document, so each document can be used alone to validate the messages
+*/
+func (f *FileGenerator) Generate(schemaResolver schema.Resolver) ([]string, error) {
+	declaredTypes, err := schemaResolver.GetTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	publishedEvents, err := schemaResolver.GetPublishedEvents()
+	if err != nil {
+		return nil, err
+	}
+
+	converter := NewConverter(definitionsPath, declaredTypes)
+
+	definitions, err := converter.Definitions()
+	if err != nil {
+		return nil, fmt.Errorf("can't convert types: %w", err)
+	}
+
+	if err := os.MkdirAll(f.outDir, 0755); err != nil {
+		return nil, fmt.Errorf("can't create output directory '%s': %w", f.outDir, err)
+	}
+
+	paths := make([]string, len(publishedEvents))
+
+	for i, event := range publishedEvents {
+		eventSchema, err := converter.PublishedEventSchema(event)
+		if err != nil {
+			return nil, fmt.Errorf("can't convert published event '%s': %w", event.Name(), err)
+		}
+
+		document := jsonc.MapSlice{
+			{Key: "$schema", Value: draft202012},
+			{Key: "title", Value: event.Name()},
+		}
+
+		if description := event.Description(); len(description) > 0 {
+			document = append(document, jsonc.MapItem{Key: "description", Value: description})
+		}
+
+		document = append(document, eventSchema...)
+
+		if len(definitions) > 0 {
+			document = append(document, jsonc.MapItem{Key: "$defs", Value: definitions})
+		}
+
+		content, err := marshalDocument(document)
+		if err != nil {
+			return nil, fmt.Errorf("can't encode published event '%s': %w", event.Name(), err)
+		}
+
+		paths[i] = filepath.Join(f.outDir, codegen.FileName(event.Name())+".schema.json")
+
+		if err := os.WriteFile(paths[i], content, 0644); err != nil {
+			return nil, fmt.Errorf("can't write published event '%s' to file '%s': %w", event.Name(), paths[i], err)
+		}
+	}
+
+	return paths, nil
+}
+
+func marshalDocument(document jsonc.MapSlice) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
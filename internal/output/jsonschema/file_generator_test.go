@@ -0,0 +1,194 @@
+package jsonschema_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/internal/output/jsonschema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+)
+
+func TestShouldWriteOneDocumentPerPublishedEvent(t *testing.T) {
+	input := strings.NewReader(`
+version: "1.0"
+name: super-cool-service
+
+events:
+  published:
+    CAKE_BURNED:
+      visibility: public
+      description: Evento disparado quando o bolo é queimado
+      attributes:
+        $ref: '#/types/Cake'
+      entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+            format: uuid
+            value: 41af6672-5b3a-4d5c-9be1-7c93dc1614e1
+    cake/eaten:
+      visibility: public
+      attributes:
+        type: object
+        nullable: true
+        properties:
+          eatenAt:
+            type: string
+            format: date-time
+            value: "2022-10-20T10:00:00Z"
+      entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+            value: "12354"
+
+types:
+  Cake:
+    type: object
+    properties:
+      layers:
+        type: integer
+        value: 5`)
+
+	schemaResolver := schema.NewBasicResolver()
+	if err := yaml.NewDecoder().Decode(input, schemaResolver); err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := t.TempDir()
+
+	paths, err := jsonschema.NewFileGenerator(outDir).Generate(schemaResolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		filepath.Join(outDir, "CAKE_BURNED.schema.json"): `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "CAKE_BURNED",
+  "description": "Evento disparado quando o bolo é queimado",
+  "type": "object",
+  "properties": {
+    "attributes": {
+      "$ref": "#/$defs/Cake"
+    },
+    "entities": {
+      "type": "object",
+      "properties": {
+        "cakeId": {
+          "type": "string",
+          "format": "uuid",
+          "examples": [
+            "41af6672-5b3a-4d5c-9be1-7c93dc1614e1"
+          ]
+        }
+      },
+      "required": [
+        "cakeId"
+      ]
+    }
+  },
+  "required": [
+    "attributes",
+    "entities"
+  ],
+  "$defs": {
+    "Cake": {
+      "type": "object",
+      "properties": {
+        "layers": {
+          "type": "integer",
+          "examples": [
+            5
+          ]
+        }
+      },
+      "required": [
+        "layers"
+      ]
+    }
+  }
+}
+`,
+		filepath.Join(outDir, "cake_eaten.schema.json"): `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "cake/eaten",
+  "type": "object",
+  "properties": {
+    "attributes": {
+      "type": [
+        "object",
+        "null"
+      ],
+      "properties": {
+        "eatenAt": {
+          "type": "string",
+          "format": "date-time",
+          "examples": [
+            "2022-10-20T10:00:00Z"
+          ]
+        }
+      },
+      "required": [
+        "eatenAt"
+      ]
+    },
+    "entities": {
+      "type": "object",
+      "properties": {
+        "cakeId": {
+          "type": "string",
+          "examples": [
+            "12354"
+          ]
+        }
+      },
+      "required": [
+        "cakeId"
+      ]
+    }
+  },
+  "required": [
+    "attributes",
+    "entities"
+  ],
+  "$defs": {
+    "Cake": {
+      "type": "object",
+      "properties": {
+        "layers": {
+          "type": "integer",
+          "examples": [
+            5
+          ]
+        }
+      },
+      "required": [
+        "layers"
+      ]
+    }
+  }
+}
+`,
+	}
+
+	if len(paths) != len(expected) {
+		t.Fatalf("expected '%d' files, received '%d'", len(expected), len(paths))
+	}
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(content) != expected[path] {
+			t.Errorf("expected '%s' content in '%s', received '%s'", expected[path], path, content)
+		}
+	}
+}
@@ -20,6 +20,7 @@ import (
 
 type Client struct {
 	host          string
+	linksBase     string
 	authorization string
 	httpClient    *http.Client
 }
@@ -39,10 +40,16 @@ func NewClient(httpClient *http.Client, host, authorization string) *Client {
 	return &Client{
 		httpClient:    httpClient,
 		host:          fmt.Sprintf("%s/wiki/rest/api", host),
+		linksBase:     fmt.Sprintf("%s/wiki", host),
 		authorization: authorization,
 	}
 }
 
+// LinksBase returns the base URL of the content links, search results don't return it
+func (c *Client) LinksBase() string {
+	return c.linksBase
+}
+
 func (c *Client) CreateContent(ctx context.Context, content *goconfluence.Content) (*goconfluence.Content, error) {
 	var result *goconfluence.Content
 	if err := c.request(ctx, "CreateContent", http.MethodPost, "content", content, &result); err != nil {
@@ -62,14 +69,28 @@ func (c *Client) UpdateContent(ctx context.Context, content *goconfluence.Conten
 }
 
 func (c *Client) FindContent(ctx context.Context, content *goconfluence.Content, start int) (*goconfluence.ContentSearch, error) {
-	req, err := c.createRequest(ctx, "FindContent", http.MethodGet, "content", nil)
+	return c.findContent(ctx, "FindContent", "space,version,ancestors", content, start)
+}
+
+// FindContentWithBody works like FindContent, but also returns the storage body of the found contents
+func (c *Client) FindContentWithBody(ctx context.Context, content *goconfluence.Content, start int) (*goconfluence.ContentSearch, error) {
+	return c.findContent(ctx, "FindContentWithBody", "space,version,ancestors,body.storage", content, start)
+}
+
+func (c *Client) findContent(
+	ctx context.Context,
+	name, expand string,
+	content *goconfluence.Content,
+	start int,
+) (*goconfluence.ContentSearch, error) {
+	req, err := c.createRequest(ctx, name, http.MethodGet, "content", nil)
 	if err != nil {
 		return nil, err
 	}
 
 	q := req.URL.Query()
 
-	q.Add("expand", "space,version,ancestors")
+	q.Add("expand", expand)
 	q.Add("start", fmt.Sprint(start))
 
 	q.Add("type", content.Type)
@@ -79,7 +100,7 @@ func (c *Client) FindContent(ctx context.Context, content *goconfluence.Content,
 	req.URL.RawQuery = q.Encode()
 
 	var result *goconfluence.ContentSearch
-	if _, err := c.doRequest("FindContent", req, &result); err != nil {
+	if _, err := c.doRequest(name, req, &result); err != nil {
 		return nil, err
 	}
 
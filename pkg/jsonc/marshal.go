@@ -2,6 +2,8 @@
 package jsonc
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"sort"
@@ -25,6 +27,49 @@ type MapItem struct {
 // MapSlice encodes map to JSON preserving the order of keys
 type MapSlice []MapItem
 
+// MarshalJSON encodes the map as a standard JSON object preserving the order of keys, without comments
+func (m MapSlice) MarshalJSON() ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	buffer.WriteByte('{')
+
+	for i := range m {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		key, err := marshalJSON(m[i].Key)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := marshalJSON(m[i].Value)
+		if err != nil {
+			return nil, fmt.Errorf("can't encode '%s' key: %w", m[i].Key, err)
+		}
+
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// marshalJSON encodes the value without escaping HTML characters, which are common in descriptions
+func marshalJSON(v interface{}) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
 type FormattedEncoder struct {
 	writer io.Writer
 }
@@ -92,6 +137,11 @@ func (f *FormattedEncoder) writeValue(v interface{}, level int, addComma bool) e
 
 			err = f.writeValue(mapSlice, level, addComma)
 		case MapSlice:
+			if len(t) < 1 {
+				err = f.writefWithComma(addComma, "{}")
+				break
+			}
+
 			err = f.write("{")
 			if err != nil {
 				break
@@ -120,6 +170,11 @@ func (f *FormattedEncoder) writeValue(v interface{}, level int, addComma bool) e
 				err = f.writeCloseBlock("}", level, addComma)
 			}
 		case []interface{}:
+			if len(t) < 1 {
+				err = f.writefWithComma(addComma, "[]")
+				break
+			}
+
 			err = f.write("[")
 			if err != nil {
 				break
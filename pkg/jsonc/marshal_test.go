@@ -1,6 +1,7 @@
 package jsonc_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
@@ -179,6 +180,25 @@ func TestEncodeCommentableValues(t *testing.T) {
 	}
 }
 
+func TestMarshalMapSliceAsStandardJSON(t *testing.T) {
+	input := jsonc.MapSlice{
+		{Key: "Z", Value: 10},
+		{Key: "A", Value: "<Yes!>"},
+		{Key: "nested", Value: jsonc.MapSlice{{Key: "b", Value: []interface{}{true, nil}}}},
+	}
+
+	result := &strings.Builder{}
+
+	encoder := json.NewEncoder(result)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(input); err != nil {
+		t.Fatalf("can't marshal value '%#v': %s", input, err)
+	}
+
+	assertString(t, `{"Z":10,"A":"<Yes!>","nested":{"b":[true,null]}}`+"\n", result.String())
+}
+
 func assertCanEncode(t *testing.T, writer io.Writer, withComments bool, input interface{}) {
 	t.Helper()
 
@@ -0,0 +1,81 @@
+// multierror package groups several errors in a single error, keeping each one available to errors.As
+package multierror
+
+import (
+	"errors"
+	"strings"
+)
+
+// Error groups several errors. Use Append to create it
+type Error struct {
+	errs []error
+}
+
+// Error returns the message of each error, one per line
+func (e *Error) Error() string {
+	messages := make([]string, len(e.errs))
+	for i := range e.errs {
+		messages[i] = e.errs[i].Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// Errors returns the grouped errors in the order they were appended
+func (e *Error) Errors() []error {
+	return e.errs
+}
+
+// As allows errors.As to find the target in any grouped error, returning the first match
+func (e *Error) As(target interface{}) bool {
+	for i := range e.errs {
+		if errors.As(e.errs[i], target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Is allows errors.Is to match any grouped error
+func (e *Error) Is(target error) bool {
+	for i := range e.errs {
+		if errors.Is(e.errs[i], target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+Append appends the "errs" to the "err", ignoring nil errors. Grouped errors are flattened.
+
+Returns nil when there are no errors, so the result can be returned directly.
+*/
+func Append(err error, errs ...error) error {
+	var result []error
+
+	for _, e := range append([]error{err}, errs...) {
+		result = append(result, Flatten(e)...)
+	}
+
+	if len(result) < 1 {
+		return nil
+	}
+
+	return &Error{errs: result}
+}
+
+// Flatten returns the grouped errors of "err", or "err" itself when it is not a *Error. Returns nil for nil errors
+func Flatten(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	if multiErr, is := err.(*Error); is {
+		return multiErr.errs
+	}
+
+	return []error{err}
+}
@@ -0,0 +1,75 @@
+package multierror_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/multierror"
+)
+
+type pathError struct {
+	path string
+}
+
+func (p *pathError) Error() string {
+	return p.path
+}
+
+func TestShouldReturnNilWithoutErrors(t *testing.T) {
+	if err := multierror.Append(nil, nil, nil); err != nil {
+		t.Errorf("expected nil error, received '%s'", err)
+	}
+
+	if errs := multierror.Flatten(nil); errs != nil {
+		t.Errorf("expected nil errors, received %v", errs)
+	}
+}
+
+func TestShouldFlattenAppendedErrors(t *testing.T) {
+	var (
+		first  = errors.New("first")
+		second = errors.New("second")
+		third  = errors.New("third")
+	)
+
+	err := multierror.Append(nil, first, nil)
+	err = multierror.Append(err, multierror.Append(second, third))
+
+	errs := multierror.Flatten(err)
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 errors, received %d", len(errs))
+	}
+
+	for i, expected := range []error{first, second, third} {
+		if errs[i] != expected {
+			t.Errorf("expected '%s' error at %d position, received '%s'", expected, i, errs[i])
+		}
+	}
+
+	if message := err.Error(); message != "first\nsecond\nthird" {
+		t.Errorf("unexpected message '%s'", message)
+	}
+}
+
+func TestShouldMatchGroupedErrors(t *testing.T) {
+	err := multierror.Append(
+		errors.New("first"),
+		fmt.Errorf("wrapped: %w", &pathError{path: "#/types/Cake"}),
+		fmt.Errorf("wrapped: %w", io.EOF),
+	)
+
+	var target *pathError
+	if !errors.As(err, &target) {
+		t.Fatal("expected to find the path error")
+	}
+
+	if target.path != "#/types/Cake" {
+		t.Errorf("expected '#/types/Cake' path, received '%s'", target.path)
+	}
+
+	if !errors.Is(err, io.EOF) {
+		t.Error("expected to match io.EOF")
+	}
+}
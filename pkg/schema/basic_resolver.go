@@ -3,7 +3,10 @@ package schema
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/multierror"
 	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
 )
 
@@ -28,12 +31,29 @@ type BasicResolver struct {
 	// hasResolved indicates that the types have been resolved
 	hasResolved bool
 
+	// definitions stores every declared definition by path, including the nested ones like object properties
+	// and event attributes, allowing references to nested definitions
+	definitions map[string]types.TypeDescriber
+	// slice of definitions paths to keep declaration order
+	definitionPaths []string
+
 	// resolvedTypes stored resolved types in all levels to prevent duplicate work
 	resolvedTypes map[string]types.TypeDescriber
 
-	// resolvingTypes stores the types being resolved to identify recursive references.
-	// we use maps for quick searches, the stored value doesn't matter
-	resolvingTypes map[string]bool
+	// resolving stores the definitions being resolved to identify recursive references.
+	// It is shared with the resolvers of external documents
+	resolving *resolvingStack
+
+	// documentPath is the absolute path of the document file, used to find the files of external references
+	documentPath string
+
+	// namespace prefixes the definitions paths of external documents in errors. Empty for the main document
+	namespace string
+
+	referenceLoader ReferenceLoader
+
+	// positions stores the line and column of the definitions by path, reported in the resolution errors
+	positions map[string][2]int
 }
 
 func (b *BasicResolver) SetProject(name string) error {
@@ -50,6 +70,27 @@ func (b *BasicResolver) SetProject(name string) error {
 	return nil
 }
 
+// SetDocumentPath sets the file path of the document, the relative paths of external references are resolved from its directory
+func (b *BasicResolver) SetDocumentPath(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("can't resolve document path '%s': %w", path, err)
+	}
+
+	b.documentPath = absPath
+	return nil
+}
+
+// SetPosition sets the line and column of the definition declared in "path", reported in its resolution errors
+func (b *BasicResolver) SetPosition(path string, line, column int) {
+	b.positions[path] = [2]int{line, column}
+}
+
+// SetReferenceLoader enables external references, such as "./common.yaml#/types/Money", using the loader
+func (b *BasicResolver) SetReferenceLoader(loader ReferenceLoader) {
+	b.referenceLoader = loader
+}
+
 func (b *BasicResolver) SetConfluencePageTitlePrefix(prefix string) {
 	b.confluencePageTitlePrefix = prefix
 }
@@ -76,6 +117,14 @@ func (b *BasicResolver) AddConfluencePage(title, spaceKey, ancestorID string) er
 	return nil
 }
 
+func (b *BasicResolver) GetProject() (*types.Project, error) {
+	if err := b.isValid(); err != nil {
+		return nil, err
+	}
+
+	return b.project, nil
+}
+
 func (b *BasicResolver) GetConfluence() (*types.Confluence, error) {
 	if err := b.isValid(); err != nil {
 		return nil, err
@@ -97,6 +146,7 @@ func (b *BasicResolver) AddType(t types.TypeDescriber) error {
 
 	b.types[string(t.Path())] = t
 	b.typePaths = append(b.typePaths, t.Path())
+	b.addDefinitions(t)
 	return nil
 }
 
@@ -127,6 +177,8 @@ func (b *BasicResolver) AddPublishedEvent(e *types.PublishedEvent) error {
 
 	b.publishedEvents[e.Name()] = e
 	b.publishedEventsNames = append(b.publishedEventsNames, e.Name())
+	b.addDefinitions(e.Attributes())
+	b.addDefinitions(e.Entities())
 	return nil
 }
 
@@ -170,13 +222,67 @@ func (b *BasicResolver) GetConsumedEvents() ([]*types.ConsumedEvent, error) {
 	return result, nil
 }
 
+// resolvingDefinition is a definition being resolved, identified by the document path and the definition path
+type resolvingDefinition struct {
+	key        string
+	definition types.TypeDescriber
+}
+
+// resolvingStack stores the definitions being resolved in resolution order, from the outermost to the innermost
+type resolvingStack struct {
+	definitions []resolvingDefinition
+}
+
+func (r *resolvingStack) push(key string, definition types.TypeDescriber) {
+	r.definitions = append(r.definitions, resolvingDefinition{
+		key:        key,
+		definition: definition,
+	})
+}
+
+func (r *resolvingStack) pop() {
+	r.definitions = r.definitions[:len(r.definitions)-1]
+}
+
+// index returns the position of the definition identified by "key" or -1 when it is not being resolved
+func (r *resolvingStack) index(key string) int {
+	for i := range r.definitions {
+		if r.definitions[i].key == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
 func NewBasicResolver() *BasicResolver {
 	return &BasicResolver{
 		types:           make(map[string]types.TypeDescriber),
 		publishedEvents: make(map[string]*types.PublishedEvent),
 		consumedEvents:  make(map[string]*types.ConsumedEvent),
+		definitions:     make(map[string]types.TypeDescriber),
 		resolvedTypes:   make(map[string]types.TypeDescriber),
-		resolvingTypes:  make(map[string]bool),
+		resolving:       &resolvingStack{},
+		positions:       make(map[string][2]int),
+	}
+}
+
+// addDefinitions indexes the definition "t" and its nested definitions by path
+func (b *BasicResolver) addDefinitions(t types.TypeDescriber) {
+	if _, exists := b.definitions[t.Path()]; !exists {
+		b.definitionPaths = append(b.definitionPaths, t.Path())
+	}
+
+	b.definitions[t.Path()] = t
+
+	switch t := t.(type) {
+	case *types.Array:
+		b.addDefinitions(t.Items())
+	case *types.Object:
+		properties := t.Properties()
+		for i := range properties {
+			b.addDefinitions(properties[i])
+		}
 	}
 }
 
@@ -197,29 +303,37 @@ func (b *BasicResolver) resolve() error {
 		return nil
 	}
 
-	for path := range b.types {
+	// The errors are collected in declaration order to report every unresolved definition at once
+	var errs error
+
+	for _, path := range b.typePaths {
 		resolvedType, err := b.getResolvedType(b.types[path])
 		if err != nil {
-			return err
+			errs = multierror.Append(errs, err)
+			continue
 		}
 
 		b.types[path] = resolvedType
 	}
 
-	for name := range b.publishedEvents {
+	for _, name := range b.publishedEventsNames {
 		attributesType, err := b.getResolvedType(b.publishedEvents[name].Attributes())
 		if err != nil {
-			return err
+			errs = multierror.Append(errs, err)
+		} else {
+			b.publishedEvents[name].SetAttributes(attributesType)
 		}
 
-		b.publishedEvents[name].SetAttributes(attributesType)
-
 		entities, err := b.getResolvedType(b.publishedEvents[name].Entities())
 		if err != nil {
-			return err
+			errs = multierror.Append(errs, err)
+		} else {
+			b.publishedEvents[name].SetEntities(entities)
 		}
+	}
 
-		b.publishedEvents[name].SetEntities(entities)
+	if errs != nil {
+		return errs
 	}
 
 	b.hasResolved = true
@@ -231,11 +345,20 @@ func (b *BasicResolver) getResolvedType(t types.TypeDescriber) (types.TypeDescri
 		return resolved, nil
 	}
 
+	b.resolving.push(b.documentPath+t.Path(), t)
 	resolved, err := b.resolveType(t)
+	b.resolving.pop()
+
 	if err != nil {
 		return nil, err
 	}
 
+	// A recursive reference depends on the definition being resolved, like a type referencing the type that references it,
+	// so it is resolved again in other contexts
+	if _, is := resolved.(*types.RecursiveReference); is {
+		return resolved, nil
+	}
+
 	b.resolvedTypes[resolved.Path()] = resolved
 	return resolved, nil
 }
@@ -251,14 +374,15 @@ func (b *BasicResolver) resolveType(t types.TypeDescriber) (types.TypeDescriber,
 	case *types.Scalar:
 		return t, nil
 	default:
-		return nil, fmt.Errorf("unkown type '%T' of definition '%s'", t, t.Path())
+		return nil, fmt.Errorf("unkown type '%T' of definition '%s'", t, b.qualifiedPath(t.Path()))
 	}
 }
 
 func (b *BasicResolver) resolveArrayType(arrayType *types.Array) (types.TypeDescriber, error) {
+	// The items errors already describe the items path
 	itemsType, err := b.getResolvedType(arrayType.Items())
 	if err != nil {
-		return nil, fmt.Errorf("can't resolve items from '%s': %w", arrayType.Path(), err)
+		return nil, err
 	}
 
 	arrayType.SetItems(itemsType)
@@ -266,40 +390,95 @@ func (b *BasicResolver) resolveArrayType(arrayType *types.Array) (types.TypeDesc
 }
 
 func (b *BasicResolver) resolveObjectType(objectType *types.Object) (types.TypeDescriber, error) {
+	var errs error
+
 	properties := objectType.Properties()
 	for i := range properties {
 		propertyType, err := b.getResolvedType(properties[i])
 		if err != nil {
-			return nil, err
+			errs = multierror.Append(errs, err)
+			continue
 		}
 
 		properties[i] = propertyType
 	}
 
+	if errs != nil {
+		return nil, errs
+	}
+
 	objectType.SetProperties(properties)
 	return objectType, nil
 }
 
 func (b *BasicResolver) resolveReferenceType(referenceType *types.Reference) (types.TypeDescriber, error) {
-	targetType, exists := b.types[referenceType.Reference()]
+	targetResolver, targetPath, err := b.referenceTarget(referenceType)
+	if err != nil {
+		return nil, err
+	}
+
+	targetType, exists := targetResolver.definitions[targetPath]
 	if !exists {
-		return nil, fmt.Errorf("definition '%s' referenced in '%s' not found in declared types", referenceType.Reference(), referenceType.Path())
+		err := fmt.Errorf(
+			"definition '%s' referenced in '%s' not found in declared definitions",
+			referenceType.Reference(),
+			b.qualifiedPath(referenceType.Path()),
+		)
+
+		if suggestion := targetResolver.suggestDefinitionPath(targetPath); len(suggestion) > 0 {
+			// Keep the file of external references in the suggestion
+			err = fmt.Errorf("%w, did you mean '%s'?", err, strings.TrimSuffix(referenceType.Reference(), targetPath)+suggestion)
+		}
+
+		return nil, b.newDefinitionError(referenceType.Path(), err)
 	}
 
-	if _, exists := b.resolvingTypes[referenceType.Path()]; exists {
-		return nil, fmt.Errorf("recursive reference dected for definition '%s'", referenceType.Path())
+	// The document path identifies the definitions of each file, allowing to detect recursive references across files
+	if index := b.resolving.index(targetResolver.documentPath + targetPath); index >= 0 {
+		return b.resolveRecursiveReference(referenceType, index)
 	}
-	b.resolvingTypes[referenceType.Path()] = true
 
-	targetType, err := b.getResolvedType(targetType)
+	targetResolver.resolving = b.resolving
+	targetType, err = targetResolver.getResolvedType(targetType)
+
 	if err != nil {
-		return nil, fmt.Errorf("can't resolve '%s' reference: %w", referenceType.Path(), err)
-	}
+		// The external definitions are not resolved by this resolver, so the references must report their errors
+		if targetResolver != b {
+			return nil, err
+		}
 
-	delete(b.resolvingTypes, referenceType.Path())
+		// The underlying error is kept, since it can only be detected through this reference, like a recursion without end
+		return nil, b.newDefinitionError(referenceType.Path(), fmt.Errorf(
+			"can't resolve '%s' reference: definition '%s' is invalid: %w",
+			referenceType.Path(),
+			referenceType.Reference(),
+			err,
+		))
+	}
 
-	// Recreate the type definition to override generic infomation
+	// Recreate the type definition to override generic infomation.
+	// References to other references, like nested properties declared with $ref, use the final referenced type
 	switch targetType := targetType.(type) {
+	case *types.ScalarReference:
+		return types.NewScalarReference(
+			referenceType,
+			targetType.Scalar,
+		), nil
+	case *types.ArrayReference:
+		return types.NewArrayReference(
+			referenceType,
+			targetType.Array,
+		), nil
+	case *types.ObjectReference:
+		return types.NewObjectReference(
+			referenceType,
+			targetType.Object,
+		), nil
+	case *types.RecursiveReference:
+		return types.NewRecursiveReference(
+			referenceType,
+			targetType.Target(),
+		), nil
 	case *types.Scalar:
 		return types.NewScalarReference(
 			referenceType,
@@ -316,6 +495,154 @@ func (b *BasicResolver) resolveReferenceType(referenceType *types.Reference) (ty
 			targetType,
 		), nil
 	default:
-		return nil, fmt.Errorf("type '%T' of defintion '%s' is not supported", targetType, targetType.Path())
+		return nil, fmt.Errorf("type '%T' of defintion '%s' is not supported", targetType, targetResolver.qualifiedPath(targetType.Path()))
+	}
+}
+
+/*
+resolveRecursiveReference creates a reference to the definition at "index" of the resolving stack, which contains the reference.
+The recursion is only supported through arrays or nullable definitions, otherwise the structure would be infinite
+*/
+func (b *BasicResolver) resolveRecursiveReference(referenceType *types.Reference, index int) (types.TypeDescriber, error) {
+	var (
+		cycle = b.resolving.definitions[index:]
+		// The underlying definition of the cycle, skipping the references to references
+		target types.TypeDescriber
+		// The cycle can end when it goes through an array, that can be empty, or a nullable definition
+		canEnd bool
+	)
+
+	for i := range cycle {
+		if _, is := cycle[i].definition.(*types.Reference); !is && target == nil {
+			target = cycle[i].definition
+		}
+
+		if i > 0 && (cycle[i].definition.Type() == types.ArrayType || cycle[i].definition.Nullable()) {
+			canEnd = true
+		}
+	}
+
+	if target == nil || !canEnd {
+		return nil, b.newDefinitionError(referenceType.Path(), fmt.Errorf(
+			"recursive reference detected for definition '%s', the recursion must go through an array or a nullable definition",
+			b.qualifiedPath(referenceType.Path()),
+		))
+	}
+
+	return types.NewRecursiveReference(referenceType, target), nil
+}
+
+// referenceTarget returns the resolver that declares the referenced definition and the definition path in that resolver.
+// External references, such as "./common.yaml#/types/Money", are loaded by the reference loader
+func (b *BasicResolver) referenceTarget(referenceType *types.Reference) (*BasicResolver, string, error) {
+	reference := referenceType.Reference()
+
+	fileEnd := strings.Index(reference, "#")
+	if fileEnd < 1 {
+		return b, reference, nil
 	}
+
+	if b.referenceLoader == nil {
+		return nil, "", b.newDefinitionError(referenceType.Path(), fmt.Errorf(
+			"external reference '%s' in '%s' is not supported without a reference loader",
+			reference,
+			b.qualifiedPath(referenceType.Path()),
+		))
+	}
+
+	targetResolver, err := b.referenceLoader.Load(b.documentPath, reference[:fileEnd])
+	if err != nil {
+		var errs error
+
+		// Each error of the external document is reported in its own line
+		for _, err := range multierror.Flatten(err) {
+			errs = multierror.Append(
+				errs,
+				b.newDefinitionError(
+					referenceType.Path(),
+					fmt.Errorf("can't load '%s' referenced in '%s': %w", reference, b.qualifiedPath(referenceType.Path()), err),
+				),
+			)
+		}
+
+		return nil, "", errs
+	}
+
+	return targetResolver, reference[fileEnd:], nil
+}
+
+// newDefinitionError returns the error of the definition declared in "path", with its position when it is known
+func (b *BasicResolver) newDefinitionError(path string, err error) error {
+	position := b.positions[path]
+
+	return &DefinitionError{
+		File:   b.namespace,
+		Line:   position[0],
+		Column: position[1],
+		Path:   path,
+		Err:    err,
+	}
+}
+
+// qualifiedPath prefixes the definition path with the file of external documents, identifying the originating file in errors
+func (b *BasicResolver) qualifiedPath(path string) string {
+	return b.namespace + path
+}
+
+// suggestDefinitionPath returns the declared definition path most similar to "path" or an empty string when none is similar enough
+func (b *BasicResolver) suggestDefinitionPath(path string) string {
+	var (
+		suggestion string
+		// Allows about one typo every three characters of the definition name, ignoring the common prefix like "#/types/"
+		maxDistance = len(path[strings.LastIndex(path, "/")+1:])/3 + 1
+	)
+
+	for _, definitionPath := range b.definitionPaths {
+		if distance := levenshteinDistance(path, definitionPath); distance <= maxDistance {
+			suggestion, maxDistance = definitionPath, distance-1
+		}
+	}
+
+	return suggestion
+}
+
+// levenshteinDistance returns the minimum number of single character edits to change "a" into "b"
+func levenshteinDistance(a, b string) int {
+	var (
+		runesA, runesB = []rune(a), []rune(b)
+		previous       = make([]int, len(runesB)+1)
+		current        = make([]int, len(runesB)+1)
+	)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := range runesA {
+		current[0] = i + 1
+
+		for j := range runesB {
+			cost := 1
+			if runesA[i] == runesB[j] {
+				cost = 0
+			}
+
+			current[j+1] = minInt(previous[j+1]+1, current[j]+1, previous[j]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(runesB)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+
+	return result
 }
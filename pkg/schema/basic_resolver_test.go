@@ -2,9 +2,11 @@ package schema_test
 
 import (
 	"fmt"
+	"path/filepath"
 	"reflect"
 	"testing"
 
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/multierror"
 	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
 	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
 )
@@ -335,6 +337,209 @@ func TestShouldIdentifyRecursiveReference(t *testing.T) {
 	}
 }
 
+func TestShouldReportAllUnresolvedReferencesWithSuggestions(t *testing.T) {
+	resolver := schema.NewBasicResolver()
+	assertNoError(t, resolver.SetProject("bolo"))
+
+	cakeShape, err := types.NewScalar("CakeShape", "#/types/CakeShape", "", false, types.ScalarStringType, "", nil, "circle")
+	assertNoError(t, err)
+	assertNoError(t, resolver.AddType(cakeShape))
+
+	references := map[string]string{
+		"Shape":  "#/types/CakeShap",
+		"Flavor": "#/types/Banana",
+	}
+
+	for _, name := range []string{"Shape", "Flavor"} {
+		refType, err := types.NewReference(name, fmt.Sprintf("#/types/%s", name), "", false, references[name])
+		assertNoError(t, err)
+		assertNoError(t, resolver.AddType(refType))
+	}
+
+	_, err = resolver.GetTypes()
+
+	errs := multierror.Flatten(err)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, received %d: %v", len(errs), err)
+	}
+
+	assertString(
+		t,
+		"definition '#/types/CakeShap' referenced in '#/types/Shape' not found in declared definitions, did you mean '#/types/CakeShape'?",
+		errs[0].Error(),
+	)
+
+	assertString(
+		t,
+		"definition '#/types/Banana' referenced in '#/types/Flavor' not found in declared definitions",
+		errs[1].Error(),
+	)
+}
+
+func TestShouldResolveReferencesToNestedDefinitions(t *testing.T) {
+	resolver := decodeDocument(t, filepath.Join(t.TempDir(), "lifecycle.yaml"), `version: "1.0"
+name: nested
+events:
+  published:
+    CAKE_BURNED:
+      visibility: public
+      attributes:
+        type: object
+        properties:
+          shape:
+            $ref: '#/types/Cake/properties/shape'
+      entities:
+        type: object
+        properties:
+          id:
+            type: string
+            value: "1"
+types:
+  CakeShape:
+    type: string
+    enum:
+      - circle
+      - square
+    value: circle
+  Cake:
+    type: object
+    properties:
+      shape:
+        $ref: '#/types/CakeShape'
+        description: Formato do bolo
+      layers:
+        type: array
+        items:
+          type: integer
+          value: 3
+  Layer:
+    $ref: '#/types/Cake/properties/layers/items'
+  Attributes:
+    $ref: '#/events/published/CAKE_BURNED/attributes'
+`)
+
+	typeDefinitions, err := resolver.GetTypes()
+	assertNoError(t, err)
+
+	typesMap := typeDescriberSliceToMap(typeDefinitions)
+
+	layer := typeDefintionToRealType[*types.ScalarReference](t, typesMap["#/types/Layer"])
+	assertScalarValue(t, 3, layer)
+
+	attributes := typeDefintionToRealType[*types.ObjectReference](t, typesMap["#/types/Attributes"])
+	assertTypeExistInMap(t, "#/events/published/CAKE_BURNED/attributes/properties/shape", typeDescriberSliceToMap(attributes.Properties()))
+
+	events, err := resolver.GetPublishedEvents()
+	assertNoError(t, err)
+
+	eventAttributes := typeDefintionToRealType[*types.Object](t, events[0].Attributes())
+	shape := typeDefintionToRealType[*types.ScalarReference](t, eventAttributes.Properties()[0])
+	assertScalarValue(t, "circle", shape)
+	assertString(t, "CakeShape", shape.Reference())
+}
+
+func TestShouldResolveRecursiveReferencesThroughArraysAndNullableDefinitions(t *testing.T) {
+	resolver := decodeDocument(t, filepath.Join(t.TempDir(), "lifecycle.yaml"), `version: "1.0"
+name: recursive
+types:
+  Tree:
+    $ref: '#/types/Category'
+  Category:
+    type: object
+    description: Categoria de bolos
+    properties:
+      parent:
+        $ref: '#/types/Category'
+        nullable: true
+      children:
+        type: array
+        items:
+          $ref: '#/types/Tree'
+`)
+
+	typeDefinitions, err := resolver.GetTypes()
+	assertNoError(t, err)
+
+	typesMap := typeDescriberSliceToMap(typeDefinitions)
+
+	tree := typeDefintionToRealType[*types.ObjectReference](t, typesMap["#/types/Tree"])
+	assertString(t, "Category", tree.Reference())
+
+	category := typeDefintionToRealType[*types.Object](t, typesMap["#/types/Category"])
+	properties := typeDescriberSliceToMap(category.Properties())
+
+	parent := typeDefintionToRealType[*types.RecursiveReference](t, properties["#/types/Category/properties/parent"])
+	assertString(t, "#/types/Category", parent.Reference())
+	assertTypeDescription(t, "Categoria de bolos", parent)
+
+	if parent.Target() != category {
+		t.Errorf("expected '#/types/Category' target, received '%s'", parent.Target().Path())
+	}
+
+	children := typeDefintionToRealType[*types.Array](t, properties["#/types/Category/properties/children"])
+	child := typeDefintionToRealType[*types.RecursiveReference](t, children.Items())
+	assertString(t, "#/types/Tree", child.Reference())
+	assertString(t, types.ObjectType, child.Type())
+}
+
+func TestShouldRejectRecursiveReferencesWithoutEnd(t *testing.T) {
+	resolver := decodeDocument(t, filepath.Join(t.TempDir(), "lifecycle.yaml"), `version: "1.0"
+name: recursive
+types:
+  Category:
+    type: object
+    properties:
+      parent:
+        $ref: '#/types/Category'
+`)
+
+	_, err := resolver.GetTypes()
+	if err == nil {
+		t.Fatal("expected error, received nil")
+	}
+
+	assertString(
+		t,
+		"recursive reference detected for definition '#/types/Category/properties/parent', the recursion must go through an array or a nullable definition",
+		multierror.Flatten(err)[0].Error(),
+	)
+}
+
+func TestShouldRejectRecursiveReferencesBetweenTypesWithoutEnd(t *testing.T) {
+	resolver := decodeDocument(t, filepath.Join(t.TempDir(), "lifecycle.yaml"), `version: "1.0"
+name: recursive
+types:
+  A:
+    type: object
+    properties:
+      b:
+        $ref: '#/types/B'
+  B:
+    type: object
+    properties:
+      a:
+        $ref: '#/types/A'
+`)
+
+	_, err := resolver.GetTypes()
+	if err == nil {
+		t.Fatal("expected error, received nil")
+	}
+
+	errs := multierror.Flatten(err)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, received '%v'", errs)
+	}
+
+	assertString(
+		t,
+		"can't resolve '#/types/A/properties/b' reference: definition '#/types/B' is invalid: "+
+			"recursive reference detected for definition '#/types/B/properties/a', "+
+			"the recursion must go through an array or a nullable definition",
+		errs[0].Error(),
+	)
+}
+
 func assertNoError(t *testing.T, err error) {
 	t.Helper()
 
@@ -407,7 +612,7 @@ func assertScalarValue(t *testing.T, expected interface{}, typeDef types.TypeDes
 	}
 }
 
-func typeDefintionToRealType[T *types.Scalar | *types.Array | *types.Object | *types.ScalarReference | *types.ArrayReference | *types.ObjectReference](
+func typeDefintionToRealType[T *types.Scalar | *types.Array | *types.Object | *types.ScalarReference | *types.ArrayReference | *types.ObjectReference | *types.RecursiveReference](
 	t *testing.T,
 	typeDef types.TypeDescriber,
 ) T {
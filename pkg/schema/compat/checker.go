@@ -0,0 +1,328 @@
+// compat package detects breaking changes between two versions of the published events
+package compat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+)
+
+const (
+	// ModeBackward ensures that readers using the new schema can read events produced with the old schema
+	ModeBackward Mode = iota
+	// ModeForward ensures that readers using the old schema can read events produced with the new schema
+	ModeForward
+	// ModeFull ensures both backward and forward compatibility
+	ModeFull
+)
+
+type Mode uint8
+
+func (m Mode) String() string {
+	switch m {
+	case ModeBackward:
+		return "backward"
+	case ModeForward:
+		return "forward"
+	case ModeFull:
+		return "full"
+	}
+
+	return "invalid"
+}
+
+func NewMode(mode string) (Mode, error) {
+	switch mode {
+	case "backward":
+		return ModeBackward, nil
+	case "forward":
+		return ModeForward, nil
+	case "full":
+		return ModeFull, nil
+	}
+
+	return Mode(255), fmt.Errorf("compatibility mode '%s' is invalid", mode)
+}
+
+// Incompatibility describes a change that breaks the compatibility mode
+type Incompatibility struct {
+	// Path of the changed definition in the published event
+	Path    string
+	Message string
+}
+
+func (i Incompatibility) String() string {
+	return fmt.Sprintf("%s: %s", i.Path, i.Message)
+}
+
+// change stores a detected change and the compatibility directions broken by it
+type change struct {
+	Incompatibility
+
+	breaksBackward bool
+	breaksForward  bool
+}
+
+type Checker struct {
+	mode Mode
+}
+
+func NewChecker(mode Mode) *Checker {
+	return &Checker{
+		mode: mode,
+	}
+}
+
+// Check compares every published event of both schemas and returns the changes that break the checker mode
+func (c *Checker) Check(oldSchema, newSchema schema.Resolver) ([]Incompatibility, error) {
+	oldEvents, err := oldSchema.GetPublishedEvents()
+	if err != nil {
+		return nil, fmt.Errorf("can't get old published events: %w", err)
+	}
+
+	newEvents, err := newSchema.GetPublishedEvents()
+	if err != nil {
+		return nil, fmt.Errorf("can't get new published events: %w", err)
+	}
+
+	newEventsMap := make(map[string]*types.PublishedEvent, len(newEvents))
+	for i := range newEvents {
+		newEventsMap[newEvents[i].Name()] = newEvents[i]
+	}
+
+	var changes []change
+
+	for i := range oldEvents {
+		path := fmt.Sprintf("#/events/published/%s", oldEvents[i].Name())
+
+		newEvent, exists := newEventsMap[oldEvents[i].Name()]
+		if !exists {
+			changes = append(changes, newBreakingChange(path, "event removed or renamed", true, true))
+			continue
+		}
+
+		changes = append(changes, c.compareEvents(path, oldEvents[i], newEvent)...)
+	}
+
+	var result []Incompatibility
+	for i := range changes {
+		if c.breaksMode(changes[i]) {
+			result = append(result, changes[i].Incompatibility)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *Checker) breaksMode(ch change) bool {
+	switch c.mode {
+	case ModeBackward:
+		return ch.breaksBackward
+	case ModeForward:
+		return ch.breaksForward
+	default:
+		return ch.breaksBackward || ch.breaksForward
+	}
+}
+
+func (c *Checker) compareEvents(path string, oldEvent, newEvent *types.PublishedEvent) []change {
+	var changes []change
+
+	// Reducing the visibility breaks the systems that are no longer allowed to consume the event
+	if newEvent.Visibility() < oldEvent.Visibility() {
+		changes = append(changes, newBreakingChange(
+			path,
+			fmt.Sprintf("visibility changed from '%s' to '%s'", oldEvent.Visibility(), newEvent.Visibility()),
+			true,
+			true,
+		))
+	}
+
+	changes = append(changes, c.compareTypes(path+"/attributes", oldEvent.Attributes(), newEvent.Attributes())...)
+	changes = append(changes, c.compareTypes(path+"/entities", oldEvent.Entities(), newEvent.Entities())...)
+
+	return changes
+}
+
+func (c *Checker) compareTypes(path string, oldType, newType types.TypeDescriber) []change {
+	if oldType.Type() != newType.Type() {
+		return []change{
+			newBreakingChange(
+				path,
+				fmt.Sprintf("type changed from '%s' to '%s'", oldType.Type(), newType.Type()),
+				true,
+				true,
+			),
+		}
+	}
+
+	var changes []change
+
+	// The readers of the old schema can't handle the null values produced with the new schema
+	if !oldType.Nullable() && newType.Nullable() {
+		changes = append(changes, newBreakingChange(path, "became nullable", false, true))
+	} else if oldType.Nullable() && !newType.Nullable() {
+		changes = append(changes, newBreakingChange(path, "is no longer nullable", true, false))
+	}
+
+	oldRecursive, oldIsRecursive := oldType.(*types.RecursiveReference)
+	newRecursive, newIsRecursive := newType.(*types.RecursiveReference)
+
+	// The referenced definitions of recursive references are compared in their own path, expanding them would never end
+	switch {
+	case oldIsRecursive && newIsRecursive:
+		if oldRecursive.Reference() != newRecursive.Reference() {
+			changes = append(changes, newBreakingChange(
+				path,
+				fmt.Sprintf("recursive reference changed from '%s' to '%s'", oldRecursive.Reference(), newRecursive.Reference()),
+				true,
+				true,
+			))
+		}
+
+		return changes
+	// The structure of a definition can't be compared with a recursive reference without expanding it
+	case oldIsRecursive:
+		return append(changes, newBreakingChange(
+			path,
+			fmt.Sprintf("recursive reference '%s' replaced by definition", oldRecursive.Reference()),
+			true,
+			true,
+		))
+	case newIsRecursive:
+		return append(changes, newBreakingChange(
+			path,
+			fmt.Sprintf("definition replaced by recursive reference '%s'", newRecursive.Reference()),
+			true,
+			true,
+		))
+	}
+
+	switch oldType := oldType.(type) {
+	case types.ScalarDescriber:
+		changes = append(changes, c.compareScalars(path, oldType, newType.(types.ScalarDescriber))...)
+	case types.ArrayDescriber:
+		changes = append(changes, c.compareTypes(path+"/items", oldType.Items(), newType.(types.ArrayDescriber).Items())...)
+	case types.ObjectDescriber:
+		changes = append(changes, c.compareObjects(path, oldType, newType.(types.ObjectDescriber))...)
+	}
+
+	return changes
+}
+
+func (c *Checker) compareScalars(path string, oldType, newType types.ScalarDescriber) []change {
+	var changes []change
+
+	if oldType.Format() != newType.Format() {
+		changes = append(changes, newBreakingChange(
+			path,
+			fmt.Sprintf("format changed from '%s' to '%s'", oldType.Format(), newType.Format()),
+			true,
+			true,
+		))
+	}
+
+	switch {
+	case !oldType.HasEnum() && newType.HasEnum():
+		changes = append(changes, newBreakingChange(path, "enum added", true, false))
+	case oldType.HasEnum() && !newType.HasEnum():
+		changes = append(changes, newBreakingChange(path, "enum removed", false, true))
+	case oldType.HasEnum() && newType.HasEnum():
+		if removed := enumDifference(oldType.Enum(), newType.Enum()); len(removed) > 0 {
+			changes = append(changes, newBreakingChange(
+				path,
+				fmt.Sprintf("enum values removed: %s", strings.Join(removed, ", ")),
+				true,
+				false,
+			))
+		}
+
+		if added := enumDifference(newType.Enum(), oldType.Enum()); len(added) > 0 {
+			changes = append(changes, newBreakingChange(
+				path,
+				fmt.Sprintf("enum values added: %s", strings.Join(added, ", ")),
+				false,
+				true,
+			))
+		}
+	}
+
+	return changes
+}
+
+func (c *Checker) compareObjects(path string, oldType, newType types.ObjectDescriber) []change {
+	var (
+		changes []change
+
+		oldProperties = oldType.Properties()
+		newProperties = newType.Properties()
+
+		newPropertiesMap = make(map[string]types.TypeDescriber, len(newProperties))
+		oldPropertiesMap = make(map[string]types.TypeDescriber, len(oldProperties))
+	)
+
+	for i := range newProperties {
+		newPropertiesMap[newProperties[i].Name()] = newProperties[i]
+	}
+
+	for i := range oldProperties {
+		oldPropertiesMap[oldProperties[i].Name()] = oldProperties[i]
+		propertyPath := fmt.Sprintf("%s/%s", path, oldProperties[i].Name())
+
+		newProperty, exists := newPropertiesMap[oldProperties[i].Name()]
+		if !exists {
+			changes = append(changes, newBreakingChange(propertyPath, "property removed or renamed", false, true))
+			continue
+		}
+
+		changes = append(changes, c.compareTypes(propertyPath, oldProperties[i], newProperty)...)
+	}
+
+	for i := range newProperties {
+		if _, exists := oldPropertiesMap[newProperties[i].Name()]; exists {
+			continue
+		}
+
+		// Nullable properties accept the absence of value in events produced with the old schema
+		if !newProperties[i].Nullable() {
+			changes = append(changes, newBreakingChange(
+				fmt.Sprintf("%s/%s", path, newProperties[i].Name()),
+				"property added without being nullable",
+				true,
+				false,
+			))
+		}
+	}
+
+	return changes
+}
+
+func newBreakingChange(path, message string, breaksBackward, breaksForward bool) change {
+	return change{
+		Incompatibility: Incompatibility{
+			Path:    path,
+			Message: message,
+		},
+		breaksBackward: breaksBackward,
+		breaksForward:  breaksForward,
+	}
+}
+
+// enumDifference returns the values of "a" that are not in "b"
+func enumDifference(a, b []interface{}) []string {
+	values := make(map[string]bool, len(b))
+	for i := range b {
+		values[fmt.Sprint(b[i])] = true
+	}
+
+	var result []string
+	for i := range a {
+		if value := fmt.Sprint(a[i]); !values[value] {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
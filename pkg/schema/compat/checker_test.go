@@ -0,0 +1,301 @@
+package compat_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/compat"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+)
+
+const oldDefinition = `
+version: "1.0"
+name: super-cool-service
+
+events:
+  published:
+    CAKE_BURNED:
+      visibility: public
+      attributes:
+        $ref: '#/types/Cake'
+      entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+            value: "12354"
+
+    CAKE_EATEN:
+      visibility: public
+      attributes:
+        type: object
+        properties:
+          id:
+            type: string
+            value: "12354"
+      entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+            value: "12354"
+
+types:
+  Cake:
+    type: object
+    properties:
+      id:
+        type: string
+        value: "12354"
+      layers:
+        type: integer
+        value: 5
+      shape:
+        type: string
+        enum:
+          - squad
+          - circle
+        value: circle
+      name:
+        type: string
+        value: Bolo`
+
+const newDefinition = `
+version: "1.0"
+name: super-cool-service
+
+events:
+  published:
+    CAKE_BURNED:
+      visibility: protected
+      attributes:
+        $ref: '#/types/Cake'
+      entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+            value: "12354"
+
+types:
+  Cake:
+    type: object
+    properties:
+      id:
+        type: integer
+        value: 12354
+      layers:
+        type: integer
+        nullable: true
+        value: 5
+      shape:
+        type: string
+        enum:
+          - circle
+        value: circle
+      flavour:
+        type: string
+        value: chocolate`
+
+func TestShouldDetectIncompatibleChanges(t *testing.T) {
+	testCases := []struct {
+		mode     compat.Mode
+		expected []string
+	}{
+		{
+			mode: compat.ModeBackward,
+			expected: []string{
+				"#/events/published/CAKE_BURNED: visibility changed from 'public' to 'protected'",
+				"#/events/published/CAKE_BURNED/attributes/id: type changed from 'string' to 'integer'",
+				"#/events/published/CAKE_BURNED/attributes/shape: enum values removed: squad",
+				"#/events/published/CAKE_BURNED/attributes/flavour: property added without being nullable",
+				"#/events/published/CAKE_EATEN: event removed or renamed",
+			},
+		},
+		{
+			mode: compat.ModeForward,
+			expected: []string{
+				"#/events/published/CAKE_BURNED: visibility changed from 'public' to 'protected'",
+				"#/events/published/CAKE_BURNED/attributes/id: type changed from 'string' to 'integer'",
+				"#/events/published/CAKE_BURNED/attributes/layers: became nullable",
+				"#/events/published/CAKE_BURNED/attributes/name: property removed or renamed",
+				"#/events/published/CAKE_EATEN: event removed or renamed",
+			},
+		},
+		{
+			mode: compat.ModeFull,
+			expected: []string{
+				"#/events/published/CAKE_BURNED: visibility changed from 'public' to 'protected'",
+				"#/events/published/CAKE_BURNED/attributes/id: type changed from 'string' to 'integer'",
+				"#/events/published/CAKE_BURNED/attributes/layers: became nullable",
+				"#/events/published/CAKE_BURNED/attributes/shape: enum values removed: squad",
+				"#/events/published/CAKE_BURNED/attributes/name: property removed or renamed",
+				"#/events/published/CAKE_BURNED/attributes/flavour: property added without being nullable",
+				"#/events/published/CAKE_EATEN: event removed or renamed",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("should check %s compatibility", testCase.mode), func(t *testing.T) {
+			incompatibilities, err := compat.NewChecker(testCase.mode).Check(
+				newSchemaResolver(t, oldDefinition),
+				newSchemaResolver(t, newDefinition),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var result []string
+			for i := range incompatibilities {
+				result = append(result, incompatibilities[i].String())
+			}
+
+			if !reflect.DeepEqual(result, testCase.expected) {
+				t.Errorf("expected '%s', received '%s'", strings.Join(testCase.expected, "\n"), strings.Join(result, "\n"))
+			}
+		})
+	}
+}
+
+func TestShouldNotReportIdenticalSchemas(t *testing.T) {
+	incompatibilities, err := compat.NewChecker(compat.ModeFull).Check(
+		newSchemaResolver(t, oldDefinition),
+		newSchemaResolver(t, oldDefinition),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(incompatibilities) > 0 {
+		t.Errorf("expected no incompatibilities, received '%v'", incompatibilities)
+	}
+}
+
+func TestShouldDetectChangedRecursiveReferences(t *testing.T) {
+	const definition = `
+version: "1.0"
+name: super-cool-service
+
+events:
+  published:
+    CATEGORY_CREATED:
+      visibility: public
+      attributes:
+        $ref: '#/types/Category'
+      entities:
+        type: object
+        properties:
+          categoryId:
+            type: string
+            value: "12354"
+
+types:
+  Parent:
+    $ref: '#/types/Category'
+  Category:
+    type: object
+    properties:
+      parent:
+        $ref: '%s'
+        nullable: true`
+
+	incompatibilities, err := compat.NewChecker(compat.ModeFull).Check(
+		newSchemaResolver(t, fmt.Sprintf(definition, "#/types/Category")),
+		newSchemaResolver(t, fmt.Sprintf(definition, "#/types/Parent")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "#/events/published/CATEGORY_CREATED/attributes/parent: recursive reference changed from '#/types/Category' to '#/types/Parent'"
+	if len(incompatibilities) != 1 || incompatibilities[0].String() != expected {
+		t.Errorf("expected '%s', received '%v'", expected, incompatibilities)
+	}
+}
+
+func TestShouldDetectDefinitionsReplacingRecursiveReferences(t *testing.T) {
+	const definition = `
+version: "1.0"
+name: super-cool-service
+
+events:
+  published:
+    CATEGORY_CREATED:
+      visibility: public
+      attributes:
+        $ref: '#/types/Category'
+      entities:
+        type: object
+        properties:
+          categoryId:
+            type: string
+            value: "12354"
+
+types:
+  Category:
+    type: object
+    properties:
+      parent:
+%s`
+
+	const (
+		recursiveParent = `        $ref: '#/types/Category'
+        nullable: true`
+		objectParent = `        type: object
+        nullable: true
+        properties:
+          id:
+            type: string
+            value: "12354"`
+	)
+
+	testCases := []struct {
+		name     string
+		old, new string
+		expected string
+	}{
+		{
+			name:     "should detect recursive reference replaced by definition",
+			old:      recursiveParent,
+			new:      objectParent,
+			expected: "#/events/published/CATEGORY_CREATED/attributes/parent: recursive reference '#/types/Category' replaced by definition",
+		},
+		{
+			name:     "should detect definition replaced by recursive reference",
+			old:      objectParent,
+			new:      recursiveParent,
+			expected: "#/events/published/CATEGORY_CREATED/attributes/parent: definition replaced by recursive reference '#/types/Category'",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			incompatibilities, err := compat.NewChecker(compat.ModeFull).Check(
+				newSchemaResolver(t, fmt.Sprintf(definition, testCase.old)),
+				newSchemaResolver(t, fmt.Sprintf(definition, testCase.new)),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(incompatibilities) != 1 || incompatibilities[0].String() != testCase.expected {
+				t.Errorf("expected '%s', received '%v'", testCase.expected, incompatibilities)
+			}
+		})
+	}
+}
+
+func newSchemaResolver(t *testing.T, definition string) schema.Resolver {
+	t.Helper()
+
+	schemaResolver := schema.NewBasicResolver()
+	if err := yaml.NewDecoder().Decode(strings.NewReader(definition), schemaResolver); err != nil {
+		t.Fatal(err)
+	}
+
+	return schemaResolver
+}
@@ -0,0 +1,22 @@
+package schema
+
+// DefinitionError records an error of the resolution of a definition and the position of the definition
+type DefinitionError struct {
+	// File is the source file of external documents. Empty for the main document
+	File string
+	// Line and Column start at 1. Zero when the decoder doesn't report the positions of the definitions
+	Line   int
+	Column int
+	// Path of the definition using the "#/events/published/EVENT_NAME/attributes" format
+	Path string
+	Err  error
+}
+
+// Error returns the error of the resolution, which already describes the definition path
+func (e *DefinitionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DefinitionError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,723 @@
+// asyncapi package decodes AsyncAPI 2.x and 3.x documents, in the YAML or JSON formats, as lifecycle definitions
+package asyncapi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/multierror"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/internal/yamlnode"
+	yamlParser "github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	visibilityExtension = "x-visibility"
+	moduleExtension     = "x-module"
+	entitiesExtension   = "x-entities"
+	confluenceExtension = "x-confluence"
+)
+
+type decoder struct{}
+
+func NewDecoder() parser.Decoder {
+	return &decoder{}
+}
+
+/*
+Decode decodes the AsyncAPI document and stores it in the schema:
+
+  - The "info.title" is the project name and the "x-confluence" extension declares the Confluence pages
+  - The "components.schemas" are the types, referenced as "#/types/SchemaName"
+  - The messages sent by the application are the published events and the messages received are the consumed events.
+    In AsyncAPI 2.x the application sends the messages of the "subscribe" operations and receives the messages of
+    the "publish" operations
+  - The "x-visibility" extension or a "private", "protected" or "public" tag is the visibility of the published events
+
+Every invalid definition is reported, grouped in a *multierror.Error. Each error wraps a *yaml.DecodeError with the
+position and the document path of the invalid definition.
+*/
+func (d *decoder) Decode(definition io.Reader, schema parser.SchemaStorager) error {
+	doc := &document{
+		schema:   schema,
+		inlining: make(map[string]bool),
+	}
+	doc.NewError = doc.newError
+
+	if named, is := definition.(interface{ Name() string }); is {
+		doc.file = named.Name()
+	}
+
+	var root yaml.Node
+	if err := yaml.NewDecoder(definition).Decode(&root); err != nil {
+		return &yamlParser.DecodeError{
+			File: doc.file,
+			Err:  fmt.Errorf("can't decode asyncapi document: %w", err),
+		}
+	}
+
+	doc.root = yamlnode.ResolveAlias(&root)
+	if doc.root.Kind == yaml.DocumentNode && len(doc.root.Content) > 0 {
+		doc.root = yamlnode.ResolveAlias(doc.root.Content[0])
+	}
+
+	return doc.decode()
+}
+
+// document decodes the nodes of a single AsyncAPI document
+type document struct {
+	yamlnode.Fields
+
+	file   string
+	schema parser.SchemaStorager
+	root   *yaml.Node
+	// majorVersion of the AsyncAPI specification
+	majorVersion int
+
+	// inlining stores the references of schemas being inlined to identify recursive references
+	inlining map[string]bool
+}
+
+// message is a message of an operation. The default name is used when the message doesn't declare its name
+type message struct {
+	defaultName string
+	path        string
+	node        *yaml.Node
+}
+
+// operation is an operation of the application. Its messages are the events
+type operation struct {
+	published   bool
+	defaultName string
+	node        *yaml.Node
+	channelNode *yaml.Node
+}
+
+func (d *document) decode() error {
+	if d.root.Kind != yaml.MappingNode {
+		return d.newError(d.root, "#", errors.New("unexpected structure"))
+	}
+
+	version, err := d.StringField(d.root, "#", "asyncapi")
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case strings.HasPrefix(version, "2."):
+		d.majorVersion = 2
+	case strings.HasPrefix(version, "3."):
+		d.majorVersion = 3
+	default:
+		return d.newError(yamlnode.FieldOrParent(d.root, "asyncapi"), "#/asyncapi", fmt.Errorf("unsupported '%s' version", version))
+	}
+
+	infoNode, err := d.MappingField(d.root, "#", "info")
+	if err != nil {
+		return err
+	}
+
+	name, err := d.StringField(infoNode, "#/info", "title")
+	if err != nil {
+		return err
+	}
+
+	// The other definitions can't be stored without a project
+	if err := d.schema.SetProject(name); err != nil {
+		return d.newError(yamlnode.FieldOrParent(infoNode, "title"), "#/info/title", err)
+	}
+
+	errs := multierror.Append(d.parseConfluence(), d.parseSchemas())
+
+	if d.majorVersion == 2 {
+		return multierror.Append(errs, d.parseChannelOperations())
+	}
+
+	return multierror.Append(errs, d.parseOperations())
+}
+
+func (d *document) parseConfluence() error {
+	path := "#/" + confluenceExtension
+
+	confluenceNode, err := d.MappingField(d.root, "#", confluenceExtension)
+	if err != nil {
+		return err
+	}
+
+	pagesNode, err := d.SequenceField(confluenceNode, path, "pages")
+	if err != nil {
+		return err
+	}
+
+	var errs error
+
+	for i, pageNode := range pagesNode {
+		pagePath := fmt.Sprintf("%s/pages/%d", path, i)
+
+		pageNode = yamlnode.ResolveAlias(pageNode)
+		if pageNode.Kind != yaml.MappingNode {
+			errs = multierror.Append(errs, d.newError(pageNode, pagePath, errors.New("unexpected structure")))
+			continue
+		}
+
+		title, titleErr := d.StringField(pageNode, pagePath, "title")
+		spaceKey, spaceKeyErr := d.StringField(pageNode, pagePath, "spaceKey")
+		ancestorID, ancestorIDErr := d.StringField(pageNode, pagePath, "ancestorId")
+
+		if err := multierror.Append(titleErr, spaceKeyErr, ancestorIDErr); err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		if err := d.schema.AddConfluencePage(title, spaceKey, ancestorID); err != nil {
+			errs = multierror.Append(errs, d.newError(pageNode, pagePath, fmt.Errorf("can't add page: %w", err)))
+		}
+	}
+
+	return errs
+}
+
+func (d *document) parseSchemas() error {
+	componentsNode, err := d.MappingField(d.root, "#", "components")
+	if err != nil {
+		return err
+	}
+
+	schemasNode, err := d.MappingField(componentsNode, "#/components", "schemas")
+	if err != nil {
+		return err
+	}
+
+	var errs error
+
+	// The valid types are registered even when other types are invalid
+	for _, item := range yamlnode.MappingItems(schemasNode) {
+		name := item.Key.Value
+
+		typeDefinition, err := d.parseSchema(name, typesPath+name, schemasPath+escapePointer(name), item.Value)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		if err := d.schema.AddType(typeDefinition); err != nil {
+			errs = multierror.Append(errs, d.newError(item.Key, schemasPath+escapePointer(name), fmt.Errorf("can't register type: %w", err)))
+		}
+	}
+
+	return errs
+}
+
+// parseChannelOperations parses the operations declared in the channels of AsyncAPI 2.x documents
+func (d *document) parseChannelOperations() error {
+	channelsNode, err := d.MappingField(d.root, "#", "channels")
+	if err != nil {
+		return err
+	}
+
+	var errs error
+
+	for _, channel := range yamlnode.MappingItems(channelsNode) {
+		channelPath := "#/channels/" + escapePointer(channel.Key.Value)
+
+		channelNode, _, err := d.followReferences(channelPath, channel.Value)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		// The operations are described from the point of view of the clients: the application sends the messages
+		// that the clients subscribe to
+		for _, action := range []struct {
+			key       string
+			published bool
+		}{
+			{key: "subscribe", published: true},
+			{key: "publish", published: false},
+		} {
+			path := fmt.Sprintf("%s/%s", channelPath, action.key)
+
+			operationNode, err := d.MappingField(channelNode, channelPath, action.key)
+			if err != nil || operationNode == nil {
+				errs = multierror.Append(errs, err)
+				continue
+			}
+
+			defaultName, err := d.StringField(operationNode, path, "operationId")
+			if err != nil {
+				errs = multierror.Append(errs, err)
+				continue
+			}
+
+			if len(defaultName) < 1 {
+				defaultName = channel.Key.Value
+			}
+
+			messageNode := yamlnode.MappingValue(operationNode, "message")
+			if messageNode == nil {
+				errs = multierror.Append(errs, d.newError(operationNode, path+"/message", errors.New("the message is required")))
+				continue
+			}
+
+			messages := []message{{path: path + "/message", node: messageNode}}
+
+			// The operation can send or receive one of many messages
+			if oneOfNode := yamlnode.MappingValue(messageNode, "oneOf"); oneOfNode != nil {
+				if oneOfNode.Kind != yaml.SequenceNode {
+					errs = multierror.Append(errs, d.newError(oneOfNode, path+"/message/oneOf", errors.New("unexpected structure")))
+					continue
+				}
+
+				messages = nil
+				for i := range oneOfNode.Content {
+					messages = append(messages, message{
+						path: fmt.Sprintf("%s/message/oneOf/%d", path, i),
+						node: yamlnode.ResolveAlias(oneOfNode.Content[i]),
+					})
+				}
+			}
+
+			errs = multierror.Append(errs, d.addOperationEvents(
+				operation{
+					published:   action.published,
+					defaultName: defaultName,
+					node:        operationNode,
+					channelNode: channelNode,
+				},
+				messages,
+			))
+		}
+	}
+
+	return errs
+}
+
+// parseOperations parses the operations of AsyncAPI 3.x documents
+func (d *document) parseOperations() error {
+	operationsNode, err := d.MappingField(d.root, "#", "operations")
+	if err != nil {
+		return err
+	}
+
+	var errs error
+
+	for _, item := range yamlnode.MappingItems(operationsNode) {
+		path := "#/operations/" + escapePointer(item.Key.Value)
+
+		operationNode, _, err := d.followReferences(path, item.Value)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		action, err := d.StringField(operationNode, path, "action")
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		if action != "send" && action != "receive" {
+			errs = multierror.Append(
+				errs,
+				d.newError(yamlnode.FieldOrParent(operationNode, "action"), path+"/action", fmt.Errorf("action '%s' is invalid", action)),
+			)
+			continue
+		}
+
+		channelRefNode := yamlnode.MappingValue(operationNode, "channel")
+		if channelRefNode == nil {
+			errs = multierror.Append(errs, d.newError(operationNode, path+"/channel", errors.New("the channel is required")))
+			continue
+		}
+
+		channelNode, channelPath, err := d.followReferences(path+"/channel", channelRefNode)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		var messages []message
+
+		// The operation sends or receives all messages of the channel when it doesn't declare its messages
+		if messagesNode := yamlnode.MappingValue(operationNode, "messages"); messagesNode != nil {
+			if messagesNode.Kind != yaml.SequenceNode {
+				errs = multierror.Append(errs, d.newError(messagesNode, path+"/messages", errors.New("unexpected structure")))
+				continue
+			}
+
+			for i := range messagesNode.Content {
+				messages = append(messages, message{
+					path: fmt.Sprintf("%s/messages/%d", path, i),
+					node: yamlnode.ResolveAlias(messagesNode.Content[i]),
+				})
+			}
+		} else {
+			channelMessagesNode, err := d.MappingField(channelNode, channelPath, "messages")
+			if err != nil {
+				errs = multierror.Append(errs, err)
+				continue
+			}
+
+			for _, channelMessage := range yamlnode.MappingItems(channelMessagesNode) {
+				messages = append(messages, message{
+					defaultName: channelMessage.Key.Value,
+					path:        fmt.Sprintf("%s/messages/%s", channelPath, escapePointer(channelMessage.Key.Value)),
+					node:        channelMessage.Value,
+				})
+			}
+		}
+
+		errs = multierror.Append(errs, d.addOperationEvents(
+			operation{
+				published:   action == "send",
+				defaultName: item.Key.Value,
+				node:        operationNode,
+				channelNode: channelNode,
+			},
+			messages,
+		))
+	}
+
+	return errs
+}
+
+// addOperationEvents registers each message of the operation as an event
+func (d *document) addOperationEvents(op operation, messages []message) error {
+	var errs error
+
+	for _, msg := range messages {
+		messageNode, messagePath, err := d.followReferences(msg.path, msg.node)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		if messageNode.Kind != yaml.MappingNode {
+			errs = multierror.Append(errs, d.newError(messageNode, messagePath, errors.New("unexpected structure")))
+			continue
+		}
+
+		// The referenced messages are named by the key of their declaration, like "#/components/messages/CakeBurned"
+		if messagePath != msg.path {
+			msg.defaultName = messagePath[strings.LastIndex(messagePath, "/")+1:]
+		}
+
+		if len(msg.defaultName) < 1 {
+			msg.defaultName = op.defaultName
+		}
+
+		msg.path, msg.node = messagePath, messageNode
+
+		if op.published {
+			errs = multierror.Append(errs, d.addPublishedEvent(op, msg))
+		} else {
+			errs = multierror.Append(errs, d.addConsumedEvent(op, msg))
+		}
+	}
+
+	return errs
+}
+
+func (d *document) addPublishedEvent(op operation, msg message) error {
+	name, nameErr := d.messageName(msg)
+	if nameErr != nil {
+		return nameErr
+	}
+
+	// The definitions paths follow the lifecycle structure, allowing references to the event definitions
+	eventPath := "#/events/published/" + name
+
+	lookupNodes := []*yaml.Node{msg.node, op.node, op.channelNode}
+
+	visibility, visibilityErr := d.parseEventVisibility(msg, lookupNodes)
+	module, moduleErr := d.lookupString(msg.path, lookupNodes, moduleExtension)
+	description, descriptionErr := d.lookupString(msg.path, lookupNodes, "description", "summary")
+	attributesType, entitiesType, payloadErr := d.parsePayload(eventPath, msg)
+
+	if err := multierror.Append(visibilityErr, moduleErr, descriptionErr, payloadErr); err != nil {
+		return err
+	}
+
+	event, err := types.NewPublishdEvent(
+		name,
+		visibility,
+		module,
+		description,
+		attributesType,
+		entitiesType,
+	)
+	if err != nil {
+		return d.newError(msg.node, msg.path, err)
+	}
+
+	if err := d.schema.AddPublishedEvent(event); err != nil {
+		return d.newError(msg.node, msg.path, fmt.Errorf("can't register published event: %w", err))
+	}
+
+	return nil
+}
+
+func (d *document) addConsumedEvent(op operation, msg message) error {
+	name, nameErr := d.messageName(msg)
+	description, descriptionErr := d.lookupString(msg.path, []*yaml.Node{msg.node, op.node, op.channelNode}, "description", "summary")
+
+	if err := multierror.Append(nameErr, descriptionErr); err != nil {
+		return err
+	}
+
+	event, err := types.NewConsumedEvent(name, description)
+	if err != nil {
+		return d.newError(msg.node, msg.path, err)
+	}
+
+	if err := d.schema.AddConsumedEvent(event); err != nil {
+		return d.newError(msg.node, msg.path, fmt.Errorf("can't register consumed event: %w", err))
+	}
+
+	return nil
+}
+
+// messageName returns the declared name of the message or its default name
+func (d *document) messageName(msg message) (string, error) {
+	name, err := d.lookupString(msg.path, []*yaml.Node{msg.node}, "name", "messageId")
+	if err != nil {
+		return "", err
+	}
+
+	if len(name) < 1 {
+		name = msg.defaultName
+	}
+
+	return name, nil
+}
+
+// parseEventVisibility returns the visibility of the "x-visibility" extension or of the first visibility tag
+func (d *document) parseEventVisibility(msg message, lookupNodes []*yaml.Node) (types.EventVisibility, error) {
+	visibility, err := d.lookupString(msg.path, lookupNodes, visibilityExtension)
+	if err != nil {
+		return types.EventVisibility(0), err
+	}
+
+	if len(visibility) > 0 {
+		v, err := types.NewEventVisibility(visibility)
+		if err != nil {
+			return v, d.newError(msg.node, msg.path+"/"+visibilityExtension, err)
+		}
+
+		return v, nil
+	}
+
+	for _, node := range lookupNodes {
+		tagsNode := yamlnode.MappingValue(node, "tags")
+		if tagsNode == nil || tagsNode.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		for _, tagNode := range tagsNode.Content {
+			tagNode, _, err := d.followReferences(msg.path+"/tags", yamlnode.ResolveAlias(tagNode))
+			if err != nil {
+				return types.EventVisibility(0), err
+			}
+
+			if v, err := types.NewEventVisibility(mappingScalar(tagNode, "name")); err == nil {
+				return v, nil
+			}
+		}
+	}
+
+	return types.EventVisibility(0), d.newError(
+		msg.node,
+		msg.path,
+		fmt.Errorf("the visibility is required, use the '%s' extension or a 'private', 'protected' or 'public' tag", visibilityExtension),
+	)
+}
+
+/*
+parsePayload returns the attributes and entities of the message payload. The payload must declare the "attributes"
+and "entities" properties, like the lifecycle events, otherwise the payload is the attributes and the "x-entities"
+extension of the message declares the entities
+*/
+func (d *document) parsePayload(eventPath string, msg message) (types.TypeDescriber, types.TypeDescriber, error) {
+	payloadPath := msg.path + "/payload"
+
+	payloadNode := yamlnode.MappingValue(msg.node, "payload")
+	if payloadNode == nil {
+		return nil, nil, d.newError(msg.node, payloadPath, errors.New("the payload is required"))
+	}
+
+	schemaFormat, err := d.StringField(msg.node, msg.path, "schemaFormat")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// AsyncAPI 3.x declares the schema format in the payload with the multi format schema object
+	if schemaNode := yamlnode.MappingValue(payloadNode, "schema"); schemaNode != nil && yamlnode.MappingValue(payloadNode, "schemaFormat") != nil {
+		schemaFormat, err = d.StringField(payloadNode, payloadPath, "schemaFormat")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		payloadNode, payloadPath = schemaNode, payloadPath+"/schema"
+	}
+
+	if len(schemaFormat) > 0 && !isJSONSchemaFormat(schemaFormat) {
+		return nil, nil, d.newError(
+			yamlnode.FieldOrParent(msg.node, "schemaFormat"),
+			msg.path+"/schemaFormat",
+			fmt.Errorf("schema format '%s' not supported", schemaFormat),
+		)
+	}
+
+	envelopeNode, envelopePath, err := d.followReferences(payloadPath, payloadNode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	propertiesNode := yamlnode.MappingValue(envelopeNode, "properties")
+	attributesNode := yamlnode.MappingValue(propertiesNode, "attributes")
+	entitiesNode := yamlnode.MappingValue(propertiesNode, "entities")
+
+	if attributesNode != nil && entitiesNode != nil {
+		attributesType, attributesErr := d.parseSchema("attributes", eventPath+"/attributes", envelopePath+"/properties/attributes", attributesNode)
+		entitiesType, entitiesErr := d.parseSchema("entities", eventPath+"/entities", envelopePath+"/properties/entities", entitiesNode)
+
+		return attributesType, entitiesType, multierror.Append(attributesErr, entitiesErr)
+	}
+
+	entitiesNode = yamlnode.MappingValue(msg.node, entitiesExtension)
+	if entitiesNode == nil {
+		return nil, nil, d.newError(
+			payloadNode,
+			payloadPath,
+			fmt.Errorf("the payload must declare the 'attributes' and 'entities' properties or the message the '%s' extension", entitiesExtension),
+		)
+	}
+
+	attributesType, attributesErr := d.parseSchema("attributes", eventPath+"/attributes", payloadPath, payloadNode)
+	entitiesType, entitiesErr := d.parseSchema("entities", eventPath+"/entities", msg.path+"/"+entitiesExtension, entitiesNode)
+
+	return attributesType, entitiesType, multierror.Append(attributesErr, entitiesErr)
+}
+
+// isJSONSchemaFormat indicates if the schema format is the AsyncAPI schema, a superset of JSON Schema, or JSON Schema
+func isJSONSchemaFormat(schemaFormat string) bool {
+	return strings.HasPrefix(schemaFormat, "application/vnd.aai.asyncapi") || strings.HasPrefix(schemaFormat, "application/schema+")
+}
+
+// followReferences returns the node referenced by the "$ref" keyword, following references to references, and its path
+func (d *document) followReferences(path string, node *yaml.Node) (*yaml.Node, string, error) {
+	visited := make(map[string]bool)
+
+	for {
+		referenceNode := yamlnode.MappingValue(node, "$ref")
+		if referenceNode == nil {
+			return node, path, nil
+		}
+
+		reference := referenceNode.Value
+		if visited[reference] {
+			return nil, "", d.newError(referenceNode, path+"/$ref", fmt.Errorf("recursive reference '%s' detected", reference))
+		}
+		visited[reference] = true
+
+		target, err := d.resolvePointer(reference)
+		if err != nil {
+			return nil, "", d.newError(referenceNode, path+"/$ref", err)
+		}
+
+		node, path = target, reference
+	}
+}
+
+// resolvePointer returns the node of the local reference, like "#/components/messages/CakeBurned"
+func (d *document) resolvePointer(reference string) (*yaml.Node, error) {
+	if !strings.HasPrefix(reference, "#/") {
+		return nil, fmt.Errorf("reference '%s' not supported, only local references are supported", reference)
+	}
+
+	node := d.root
+
+	for _, token := range strings.Split(reference[2:], "/") {
+		token = unescapePointer(token)
+
+		switch node.Kind {
+		case yaml.MappingNode:
+			node = yamlnode.MappingValue(node, token)
+		case yaml.SequenceNode:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node.Content) {
+				node = nil
+			} else {
+				node = yamlnode.ResolveAlias(node.Content[index])
+			}
+		default:
+			node = nil
+		}
+
+		if node == nil {
+			return nil, fmt.Errorf("reference '%s' not found", reference)
+		}
+	}
+
+	return node, nil
+}
+
+// lookupString returns the first declared value of the keys in the nodes, in the specified order
+func (d *document) lookupString(path string, nodes []*yaml.Node, keys ...string) (string, error) {
+	for _, node := range nodes {
+		for _, key := range keys {
+			value, err := d.StringField(node, path, key)
+			if err != nil || len(value) > 0 {
+				return value, err
+			}
+		}
+	}
+
+	return "", nil
+}
+
+func (d *document) newError(node *yaml.Node, path string, err error) error {
+	decodeErr := &yamlParser.DecodeError{
+		File: d.file,
+		Path: path,
+		Err:  err,
+	}
+
+	if node != nil {
+		decodeErr.Line = node.Line
+		decodeErr.Column = node.Column
+	}
+
+	return decodeErr
+}
+
+// setPosition stores the position of the reference in the schemas that report it in the resolution errors
+func (d *document) setPosition(path string, referenceNode *yaml.Node) {
+	if storager, is := d.schema.(parser.PositionStorager); is {
+		storager.SetPosition(path, referenceNode.Line, referenceNode.Column)
+	}
+}
+
+// mappingScalar returns the scalar value of "key" or an empty string when it doesn't exist or isn't a scalar
+func mappingScalar(mapping *yaml.Node, key string) string {
+	node := yamlnode.MappingValue(mapping, key)
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+
+	return node.Value
+}
+
+// escapePointer escapes the token of JSON pointers, like channel names with slashes
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+func unescapePointer(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
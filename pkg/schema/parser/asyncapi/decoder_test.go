@@ -0,0 +1,338 @@
+package asyncapi_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/multierror"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/asyncapi"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+)
+
+const asyncAPI2Document = `
+asyncapi: 2.6.0
+info:
+  title: super-cool-service
+  version: 1.0.0
+x-confluence:
+  pages:
+    - title: Eventos
+      spaceKey: SPACEKEY
+      ancestorId: "123456789"
+channels:
+  cake/burned:
+    subscribe:
+      operationId: publishCakeBurned
+      tags:
+        - name: public
+      message:
+        $ref: '#/components/messages/CAKE_BURNED'
+  cake/purchased:
+    publish:
+      message:
+        name: CAKE_PURCHASED
+        description: Usado para inciar o processo de fazer o bolo
+        payload:
+          type: object
+          properties:
+            cakeId:
+              type: string
+components:
+  messages:
+    CAKE_BURNED:
+      description: Evento disparado quando o bolo é queimado
+      x-module: cooker
+      payload:
+        type: object
+        properties:
+          attributes:
+            $ref: '#/components/schemas/Cake'
+          entities:
+            type: object
+            properties:
+              cakeId:
+                type: string
+                format: uuid
+                examples:
+                  - 41af6672-5b3a-4d5c-9be1-7c93dc1614e1
+  schemas:
+    Cake:
+      type: object
+      description: Representa um bolo
+      properties:
+        layers:
+          type: integer
+          example: 5
+        weight:
+          type: [number, "null"]
+        shape:
+          type: string
+          enum: [squad, circle]
+`
+
+const asyncAPI3Document = `
+asyncapi: 3.0.0
+info:
+  title: super-cool-service
+  version: 1.0.0
+channels:
+  cakeBurned:
+    address: cake/burned
+    messages:
+      CakeBurned:
+        $ref: '#/components/messages/CakeBurned'
+operations:
+  sendCakeBurned:
+    action: send
+    x-visibility: protected
+    channel:
+      $ref: '#/channels/cakeBurned'
+components:
+  messages:
+    CakeBurned:
+      summary: Evento disparado quando o bolo é queimado
+      x-entities:
+        type: object
+        properties:
+          cakeId:
+            type: string
+      payload:
+        schemaFormat: application/vnd.aai.asyncapi+json;version=3.0.0
+        schema:
+          type: object
+          properties:
+            burnedAt:
+              type: string
+              format: date-time
+`
+
+func TestShouldDecodeAsyncAPI2Document(t *testing.T) {
+	resolver := decodeDocument(t, asyncAPI2Document)
+
+	confluence, err := resolver.GetConfluence()
+	assertNoError(t, err)
+
+	if pages := confluence.Pages(); len(pages) != 1 || pages[0].SpaceKey() != "SPACEKEY" {
+		t.Errorf("unexpected confluence pages %v", pages)
+	}
+
+	typeDefinitions, err := resolver.GetTypes()
+	assertNoError(t, err)
+
+	if len(typeDefinitions) != 1 {
+		t.Fatalf("expected 1 type, received %d", len(typeDefinitions))
+	}
+
+	cake := typeDefinitionTo[*types.Object](t, typeDefinitions[0])
+	assertString(t, "#/types/Cake", cake.Path())
+	assertString(t, "Representa um bolo", cake.Description())
+
+	properties := cake.Properties()
+	if len(properties) != 3 {
+		t.Fatalf("expected 3 properties, received %d", len(properties))
+	}
+
+	if value := typeDefinitionTo[*types.Scalar](t, properties[0]).Value(); value != 5 {
+		t.Errorf("expected 5 layers, received '%v'", value)
+	}
+
+	weight := typeDefinitionTo[*types.Scalar](t, properties[1])
+	if !weight.Nullable() || weight.Type() != types.ScalarNumberType {
+		t.Errorf("expected nullable number weight, received '%s' nullable '%t'", weight.Type(), weight.Nullable())
+	}
+
+	if value := typeDefinitionTo[*types.Scalar](t, properties[2]).Value(); value != "squad" {
+		t.Errorf("expected the first enum value as example, received '%v'", value)
+	}
+
+	publishedEvents, err := resolver.GetPublishedEvents()
+	assertNoError(t, err)
+
+	if len(publishedEvents) != 1 {
+		t.Fatalf("expected 1 published event, received %d", len(publishedEvents))
+	}
+
+	event := publishedEvents[0]
+	assertString(t, "CAKE_BURNED", event.Name())
+	assertString(t, "cooker", event.Module())
+	assertString(t, "Evento disparado quando o bolo é queimado", event.Description())
+
+	if event.Visibility() != types.EventPublic {
+		t.Errorf("expected public visibility, received '%s'", event.Visibility())
+	}
+
+	assertString(t, "Cake", typeDefinitionTo[*types.ObjectReference](t, event.Attributes()).Reference())
+
+	cakeID := typeDefinitionTo[*types.Object](t, event.Entities()).Properties()[0]
+	assertString(t, "#/events/published/CAKE_BURNED/entities/properties/cakeId", cakeID.Path())
+
+	consumedEvents, err := resolver.GetConsumedEvents()
+	assertNoError(t, err)
+
+	if len(consumedEvents) != 1 || consumedEvents[0].Name() != "CAKE_PURCHASED" {
+		t.Errorf("unexpected consumed events %v", consumedEvents)
+	}
+}
+
+func TestShouldDecodeAsyncAPI3Document(t *testing.T) {
+	resolver := decodeDocument(t, asyncAPI3Document)
+
+	publishedEvents, err := resolver.GetPublishedEvents()
+	assertNoError(t, err)
+
+	if len(publishedEvents) != 1 {
+		t.Fatalf("expected 1 published event, received %d", len(publishedEvents))
+	}
+
+	event := publishedEvents[0]
+	assertString(t, "CakeBurned", event.Name())
+	assertString(t, "Evento disparado quando o bolo é queimado", event.Description())
+
+	if event.Visibility() != types.EventProtected {
+		t.Errorf("expected protected visibility, received '%s'", event.Visibility())
+	}
+
+	burnedAt := typeDefinitionTo[*types.Object](t, event.Attributes()).Properties()[0]
+	assertString(t, "date-time", typeDefinitionTo[*types.Scalar](t, burnedAt).Format())
+
+	cakeID := typeDefinitionTo[*types.Object](t, event.Entities()).Properties()[0]
+	assertString(t, "#/events/published/CakeBurned/entities/properties/cakeId", cakeID.Path())
+}
+
+func TestShouldDecodeNullableReferencesDeclaredWithOneOf(t *testing.T) {
+	resolver := decodeDocument(t, `
+asyncapi: 3.0.0
+info:
+  title: super-cool-service
+components:
+  schemas:
+    Category:
+      type: object
+      description: Categoria do bolo
+      properties:
+        parent:
+          description: Categoria pai
+          oneOf:
+            - $ref: '#/components/schemas/Category'
+            - type: "null"
+`)
+
+	typeDefinitions, err := resolver.GetTypes()
+	assertNoError(t, err)
+
+	parent := typeDefinitionTo[*types.Object](t, typeDefinitions[0]).Properties()[0]
+	assertString(t, "Categoria pai", parent.Description())
+
+	if !parent.Nullable() {
+		t.Errorf("expected nullable parent")
+	}
+
+	if _, is := parent.(*types.RecursiveReference); !is {
+		t.Errorf("expected recursive reference, received '%T'", parent)
+	}
+}
+
+func TestShouldReplaceNullableKeywordOfOneOfVariants(t *testing.T) {
+	resolver := decodeDocument(t, `
+asyncapi: 3.0.0
+info:
+  title: super-cool-service
+components:
+  schemas:
+    Weight:
+      oneOf:
+        - type: number
+          nullable: false
+        - type: "null"
+`)
+
+	typeDefinitions, err := resolver.GetTypes()
+	assertNoError(t, err)
+
+	if !typeDefinitions[0].Nullable() {
+		t.Errorf("expected nullable weight")
+	}
+}
+
+func TestShouldReportUnsupportedAsyncAPIDefinitions(t *testing.T) {
+	definition := `asyncapi: 2.6.0
+info:
+  title: super-cool-service
+channels:
+  cake/burned:
+    subscribe:
+      message:
+        name: CAKE_BURNED
+        payload:
+          type: object
+          properties:
+            attributes:
+              oneOf:
+                - type: string
+            entities:
+              type: object
+              properties:
+                cakeId:
+                  type: string
+`
+
+	err := asyncapi.NewDecoder().Decode(strings.NewReader(definition), schema.NewBasicResolver())
+
+	errs := multierror.Flatten(err)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, received %d: %v", len(errs), err)
+	}
+
+	for i, expected := range []yaml.DecodeError{
+		{Line: 8, Column: 9, Path: "#/channels/cake~1burned/subscribe/message"},
+		{Line: 14, Column: 17, Path: "#/channels/cake~1burned/subscribe/message/payload/properties/attributes/oneOf"},
+	} {
+		var decodeErr *yaml.DecodeError
+		if !errors.As(errs[i], &decodeErr) {
+			t.Fatalf("expected decode error, received '%s'", errs[i])
+		}
+
+		if decodeErr.Line != expected.Line || decodeErr.Column != expected.Column || decodeErr.Path != expected.Path {
+			t.Errorf("expected '%d:%d: %s', received '%s'", expected.Line, expected.Column, expected.Path, decodeErr)
+		}
+	}
+}
+
+func decodeDocument(t *testing.T, definition string) *schema.BasicResolver {
+	t.Helper()
+
+	resolver := schema.NewBasicResolver()
+	assertNoError(t, asyncapi.NewDecoder().Decode(strings.NewReader(definition), resolver))
+
+	return resolver
+}
+
+func assertNoError(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("expected no error, received '%s'", err)
+	}
+}
+
+func assertString(t *testing.T, expected, value string) {
+	t.Helper()
+
+	if value != expected {
+		t.Errorf("expected '%s', received '%s'", expected, value)
+	}
+}
+
+func typeDefinitionTo[T *types.Scalar | *types.Object | *types.ObjectReference](t *testing.T, typeDef types.TypeDescriber) T {
+	t.Helper()
+
+	result, is := typeDef.(T)
+	if !is {
+		t.Fatalf("definition '%s' expected type '%T', received '%T'", typeDef.Path(), result, typeDef)
+	}
+
+	return result
+}
@@ -0,0 +1,419 @@
+package asyncapi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/multierror"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/internal/yamlnode"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	schemasPath = "#/components/schemas/"
+	typesPath   = "#/types/"
+)
+
+// unsupportedSchemaKeywords have no equivalent in the lifecycle definitions
+var unsupportedSchemaKeywords = []string{"allOf", "anyOf", "oneOf", "not", "if", "then", "else"}
+
+/*
+parseSchema parses the JSON Schema of the "sourcePath" as the definition of the "path". The references to
+"#/components/schemas" are references to types and the other local references are inlined
+*/
+func (d *document) parseSchema(name, path, sourcePath string, schemaNode *yaml.Node) (types.TypeDescriber, error) {
+	if schemaNode.Kind != yaml.MappingNode {
+		return nil, d.newError(schemaNode, sourcePath, errors.New("unexpected structure"))
+	}
+
+	description, descriptionErr := d.StringField(schemaNode, sourcePath, "description")
+	typeKeyword, nullable, typeErr := d.schemaType(sourcePath, schemaNode)
+
+	if err := multierror.Append(descriptionErr, typeErr); err != nil {
+		return nil, err
+	}
+
+	if variantNode, variantPath := nullableVariant(schemaNode, sourcePath); variantNode != nil {
+		return d.parseSchema(name, path, variantPath, withNullable(variantNode, description))
+	}
+
+	if referenceNode := yamlnode.MappingValue(schemaNode, "$ref"); referenceNode != nil {
+		return d.parseSchemaReference(name, path, sourcePath, description, nullable, referenceNode)
+	}
+
+	var errs error
+	for _, keyword := range unsupportedSchemaKeywords {
+		if keywordNode := yamlnode.MappingValue(schemaNode, keyword); keywordNode != nil {
+			errs = multierror.Append(errs, d.newError(keywordNode, sourcePath+"/"+keyword, fmt.Errorf("keyword '%s' not supported", keyword)))
+		}
+	}
+
+	if errs != nil {
+		return nil, errs
+	}
+
+	// The type can be omitted in JSON Schema
+	if len(typeKeyword) < 1 {
+		switch {
+		case yamlnode.MappingValue(schemaNode, "properties") != nil:
+			typeKeyword = types.ObjectType
+		case yamlnode.MappingValue(schemaNode, "items") != nil:
+			typeKeyword = types.ArrayType
+		}
+	}
+
+	switch typeKeyword {
+	case types.ScalarIntegerType:
+		return parseScalarSchema[int](d, name, path, sourcePath, description, typeKeyword, nullable, schemaNode)
+	case types.ScalarNumberType:
+		return parseScalarSchema[float64](d, name, path, sourcePath, description, typeKeyword, nullable, schemaNode)
+	case types.ScalarStringType:
+		return parseScalarSchema[string](d, name, path, sourcePath, description, typeKeyword, nullable, schemaNode)
+	case types.ScalarBooleanType:
+		return parseScalarSchema[bool](d, name, path, sourcePath, description, typeKeyword, nullable, schemaNode)
+	case types.ArrayType:
+		itemsNode := yamlnode.MappingValue(schemaNode, "items")
+		if itemsNode == nil {
+			return nil, d.newError(schemaNode, sourcePath+"/items", errors.New("the items is required"))
+		}
+
+		itemsType, err := d.parseSchema("items", path+"/items", sourcePath+"/items", itemsNode)
+		if err != nil {
+			return nil, err
+		}
+
+		arrayType, err := types.NewArray(name, path, description, nullable, itemsType)
+		if err != nil {
+			return nil, d.newError(schemaNode, sourcePath, err)
+		}
+
+		return arrayType, nil
+	case types.ObjectType:
+		propertiesNode, err := d.MappingField(schemaNode, sourcePath, "properties")
+		if err != nil {
+			return nil, err
+		}
+
+		// An invalid property invalidates the object, but all invalid properties are reported
+		var properties []types.TypeDescriber
+		for _, item := range yamlnode.MappingItems(propertiesNode) {
+			property, err := d.parseSchema(
+				item.Key.Value,
+				fmt.Sprintf("%s/properties/%s", path, item.Key.Value),
+				fmt.Sprintf("%s/properties/%s", sourcePath, escapePointer(item.Key.Value)),
+				item.Value,
+			)
+			if err != nil {
+				errs = multierror.Append(errs, err)
+				continue
+			}
+
+			properties = append(properties, property)
+		}
+
+		if errs != nil {
+			return nil, errs
+		}
+
+		objectType, err := types.NewObject(name, path, description, nullable, properties)
+		if err != nil {
+			return nil, d.newError(schemaNode, sourcePath, err)
+		}
+
+		return objectType, nil
+	case "":
+		return nil, d.newError(schemaNode, sourcePath+"/type", errors.New("the type is required"))
+	default:
+		return nil, d.newError(yamlnode.FieldOrParent(schemaNode, "type"), sourcePath+"/type", fmt.Errorf("'%s' not supported", typeKeyword))
+	}
+}
+
+func (d *document) parseSchemaReference(
+	name, path, sourcePath, description string,
+	nullable bool,
+	referenceNode *yaml.Node,
+) (types.TypeDescriber, error) {
+	if referenceNode.Kind != yaml.ScalarNode {
+		return nil, d.newError(referenceNode, sourcePath+"/$ref", errors.New("must be a string"))
+	}
+
+	reference := referenceNode.Value
+
+	if strings.HasPrefix(reference, schemasPath) {
+		tokens := strings.Split(strings.TrimPrefix(reference, schemasPath), "/")
+		for i := range tokens {
+			tokens[i] = unescapePointer(tokens[i])
+		}
+
+		referenceType, err := types.NewReference(name, path, description, nullable, typesPath+strings.Join(tokens, "/"))
+		if err != nil {
+			return nil, d.newError(referenceNode, sourcePath+"/$ref", err)
+		}
+
+		d.setPosition(path, referenceNode)
+		return referenceType, nil
+	}
+
+	if d.inlining[reference] {
+		return nil, d.newError(referenceNode, sourcePath+"/$ref", fmt.Errorf("recursive reference '%s' detected", reference))
+	}
+
+	targetNode, err := d.resolvePointer(reference)
+	if err != nil {
+		return nil, d.newError(referenceNode, sourcePath+"/$ref", err)
+	}
+
+	d.inlining[reference] = true
+	defer delete(d.inlining, reference)
+
+	return d.parseSchema(name, path, reference, targetNode)
+}
+
+// schemaType returns the type keyword of the schema and if it is nullable, declared with the "null" type or the
+// "nullable" keyword
+func (d *document) schemaType(sourcePath string, schemaNode *yaml.Node) (string, bool, error) {
+	nullableNode := yamlnode.MappingValue(schemaNode, "nullable")
+	nullable := nullableNode != nil && nullableNode.Kind == yaml.ScalarNode && nullableNode.Value == "true"
+
+	typeNode := yamlnode.MappingValue(schemaNode, "type")
+	if typeNode == nil || yamlnode.IsNull(typeNode) {
+		return "", nullable, nil
+	}
+
+	switch typeNode.Kind {
+	case yaml.ScalarNode:
+		return typeNode.Value, nullable, nil
+	case yaml.SequenceNode:
+		var typeKeywords []string
+
+		for _, node := range typeNode.Content {
+			if node.Value == "null" {
+				nullable = true
+				continue
+			}
+
+			typeKeywords = append(typeKeywords, node.Value)
+		}
+
+		if len(typeKeywords) == 1 {
+			return typeKeywords[0], nullable, nil
+		}
+	}
+
+	return "", false, d.newError(typeNode, sourcePath+"/type", errors.New("only a single type, optionally with 'null', is supported"))
+}
+
+/*
+nullableVariant returns the schema of "oneOf" or "anyOf" keywords with a schema and the "null" type, which is how
+nullable references are declared, since some JSON Schema versions ignore the keywords declared with "$ref"
+*/
+func nullableVariant(schemaNode *yaml.Node, sourcePath string) (*yaml.Node, string) {
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		variantsNode := yamlnode.MappingValue(schemaNode, keyword)
+		if variantsNode == nil || variantsNode.Kind != yaml.SequenceNode || len(variantsNode.Content) != 2 {
+			continue
+		}
+
+		for i, variantNode := range variantsNode.Content {
+			otherNode := yamlnode.ResolveAlias(variantsNode.Content[1-i])
+
+			if isNullSchema(yamlnode.ResolveAlias(variantNode)) && otherNode.Kind == yaml.MappingNode {
+				return otherNode, fmt.Sprintf("%s/%s/%d", sourcePath, keyword, 1-i)
+			}
+		}
+	}
+
+	return nil, ""
+}
+
+// isNullSchema reports if the schema only declares the "null" type
+func isNullSchema(schemaNode *yaml.Node) bool {
+	if schemaNode.Kind != yaml.MappingNode || len(schemaNode.Content) != 2 {
+		return false
+	}
+
+	typeNode := yamlnode.MappingValue(schemaNode, "type")
+	return typeNode != nil && typeNode.Kind == yaml.ScalarNode && typeNode.Value == "null"
+}
+
+// withNullable returns a copy of the schema declared as nullable, with the description of the outer schema as fallback
+func withNullable(schemaNode *yaml.Node, description string) *yaml.Node {
+	nullableNode := *schemaNode
+	nullableNode.Content = make([]*yaml.Node, 0, len(schemaNode.Content)+2)
+
+	// The nullable keyword of the schema is replaced, since the first key of a mapping is the one decoded
+	for i := 0; i+1 < len(schemaNode.Content); i += 2 {
+		if schemaNode.Content[i].Value != "nullable" {
+			nullableNode.Content = append(nullableNode.Content, schemaNode.Content[i], schemaNode.Content[i+1])
+		}
+	}
+
+	nullableNode.Content = append(
+		nullableNode.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "nullable"},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"},
+	)
+
+	if len(description) > 0 && yamlnode.MappingValue(schemaNode, "description") == nil {
+		nullableNode.Content = append(
+			nullableNode.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "description"},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: description},
+		)
+	}
+
+	return &nullableNode
+}
+
+func parseScalarSchema[T scalar](
+	d *document,
+	name, path, sourcePath, description, typeKeyword string,
+	nullable bool,
+	schemaNode *yaml.Node,
+) (types.TypeDescriber, error) {
+	format, formatErr := d.StringField(schemaNode, sourcePath, "format")
+	enumValues, enumErr := parseScalarEnum[T](d, sourcePath, nullable, schemaNode)
+
+	if err := multierror.Append(formatErr, enumErr); err != nil {
+		return nil, err
+	}
+
+	value, err := parseScalarExample[T](d, sourcePath, nullable, enumValues, schemaNode)
+	if err != nil {
+		return nil, err
+	}
+
+	scalarType, err := types.NewScalar(
+		name,
+		path,
+		description,
+		nullable,
+		typeKeyword,
+		format,
+		enumValues,
+		value,
+	)
+	if err != nil {
+		return nil, d.newError(schemaNode, sourcePath, err)
+	}
+
+	return scalarType, nil
+}
+
+func parseScalarEnum[T scalar](d *document, sourcePath string, nullable bool, schemaNode *yaml.Node) ([]interface{}, error) {
+	enumNode := yamlnode.MappingValue(schemaNode, "enum")
+	if enumNode == nil || yamlnode.IsNull(enumNode) {
+		return nil, nil
+	}
+
+	if enumNode.Kind != yaml.SequenceNode {
+		return nil, d.newError(enumNode, sourcePath+"/enum", errors.New("unexpected structure"))
+	}
+
+	enumValues := make([]interface{}, len(enumNode.Content))
+
+	for i := range enumNode.Content {
+		var rawValue interface{}
+		if err := enumNode.Content[i].Decode(&rawValue); err != nil {
+			return nil, d.newError(enumNode.Content[i], sourcePath+"/enum", err)
+		}
+
+		if nullable && rawValue == nil {
+			continue
+		}
+
+		value, is := convertScalar[T](rawValue)
+		if !is {
+			return nil, d.newError(enumNode.Content[i], sourcePath+"/enum", fmt.Errorf("invalid enum type at %d position", i))
+		}
+
+		enumValues[i] = value
+	}
+
+	return enumValues, nil
+}
+
+/*
+parseScalarExample returns the example value of the schema, from the "examples", "example", "default" or "const"
+keywords. Without examples, the value is the first enum value, null for nullable schemas or the zero value of the type
+*/
+func parseScalarExample[T scalar](
+	d *document,
+	sourcePath string,
+	nullable bool,
+	enumValues []interface{},
+	schemaNode *yaml.Node,
+) (interface{}, error) {
+	var (
+		valueNode *yaml.Node
+		valuePath string
+	)
+
+	if examplesNode := yamlnode.MappingValue(schemaNode, "examples"); examplesNode != nil && examplesNode.Kind == yaml.SequenceNode && len(examplesNode.Content) > 0 {
+		valueNode, valuePath = yamlnode.ResolveAlias(examplesNode.Content[0]), sourcePath+"/examples/0"
+	} else {
+		for _, keyword := range []string{"example", "default", "const"} {
+			if valueNode = yamlnode.MappingValue(schemaNode, keyword); valueNode != nil {
+				valuePath = sourcePath + "/" + keyword
+				break
+			}
+		}
+	}
+
+	if valueNode == nil {
+		for i := range enumValues {
+			if enumValues[i] != nil {
+				return enumValues[i], nil
+			}
+		}
+
+		if nullable {
+			return nil, nil
+		}
+
+		var zero T
+		return zero, nil
+	}
+
+	var rawValue interface{}
+	if err := valueNode.Decode(&rawValue); err != nil {
+		return nil, d.newError(valueNode, valuePath, err)
+	}
+
+	if nullable && rawValue == nil {
+		return nil, nil
+	}
+
+	value, is := convertScalar[T](rawValue)
+	if !is {
+		return nil, d.newError(valueNode, valuePath, fmt.Errorf("is not of type '%T'", value))
+	}
+
+	return value, nil
+}
+
+// convertScalar converts the decoded value to the scalar type. JSON doesn't distinguish integers from numbers
+func convertScalar[T scalar](rawValue interface{}) (T, bool) {
+	var zero T
+
+	switch interface{}(zero).(type) {
+	case int:
+		if number, is := rawValue.(float64); is && number == math.Trunc(number) {
+			rawValue = int(number)
+		}
+	case float64:
+		if integer, is := rawValue.(int); is {
+			rawValue = float64(integer)
+		}
+	}
+
+	value, is := rawValue.(T)
+	return value, is
+}
+
+type scalar interface {
+	int | float64 | string | bool
+}
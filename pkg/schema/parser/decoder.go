@@ -16,6 +16,11 @@ type SchemaStorager interface {
 	AddConsumedEvent(e *types.ConsumedEvent) error
 }
 
+// PositionStorager is implemented by the schemas that report the positions of the definitions in their errors
+type PositionStorager interface {
+	SetPosition(path string, line, column int)
+}
+
 type Decoder interface {
 	Decode(r io.Reader, s SchemaStorager) error
 }
@@ -0,0 +1,117 @@
+// yamlnode package has the helpers shared by the decoders of YAML nodes, like the lifecycle and AsyncAPI decoders
+package yamlnode
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Item is an item of a mapping node
+type Item struct {
+	Key   *yaml.Node
+	Value *yaml.Node
+}
+
+// MappingItems returns the items of the mapping node in declaration order
+func MappingItems(mapping *yaml.Node) []Item {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	items := make([]Item, 0, len(mapping.Content)/2)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		items = append(items, Item{
+			Key:   mapping.Content[i],
+			Value: ResolveAlias(mapping.Content[i+1]),
+		})
+	}
+
+	return items
+}
+
+// MappingValue returns the value node of "key" or nil when it doesn't exist
+func MappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return ResolveAlias(mapping.Content[i+1])
+		}
+	}
+
+	return nil
+}
+
+// FieldOrParent returns the value node of "key" or the mapping node when the key doesn't exist,
+// the nearest position of a missing definition
+func FieldOrParent(mapping *yaml.Node, key string) *yaml.Node {
+	if node := MappingValue(mapping, key); node != nil {
+		return node
+	}
+
+	return mapping
+}
+
+func ResolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	return node
+}
+
+func IsNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+// Fields decodes the fields of mapping nodes, reporting the invalid fields with the errors of the decoder
+type Fields struct {
+	// NewError creates the error of the node in the definition "path"
+	NewError func(node *yaml.Node, path string, err error) error
+}
+
+// StringField returns the scalar value of "key" or an empty string when it doesn't exist
+func (f Fields) StringField(mapping *yaml.Node, path, key string) (string, error) {
+	node := MappingValue(mapping, key)
+	if node == nil || IsNull(node) {
+		return "", nil
+	}
+
+	if node.Kind != yaml.ScalarNode {
+		return "", f.NewError(node, fmt.Sprintf("%s/%s", path, key), errors.New("must be a string"))
+	}
+
+	return node.Value, nil
+}
+
+// MappingField returns the mapping node of "key". Returns nil when the key doesn't exist or is null
+func (f Fields) MappingField(mapping *yaml.Node, path, key string) (*yaml.Node, error) {
+	node := MappingValue(mapping, key)
+	if node == nil || IsNull(node) {
+		return nil, nil
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return nil, f.NewError(node, fmt.Sprintf("%s/%s", path, key), errors.New("unexpected structure"))
+	}
+
+	return node, nil
+}
+
+// SequenceField returns the items of the sequence node of "key"
+func (f Fields) SequenceField(mapping *yaml.Node, path, key string) ([]*yaml.Node, error) {
+	node := MappingValue(mapping, key)
+	if node == nil || IsNull(node) {
+		return nil, nil
+	}
+
+	if node.Kind != yaml.SequenceNode {
+		return nil, f.NewError(node, fmt.Sprintf("%s/%s", path, key), errors.New("unexpected structure"))
+	}
+
+	return node.Content, nil
+}
@@ -0,0 +1,93 @@
+// json package decodes the lifecycle definitions in the JSON format, which has the same structure of the YAML format
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser"
+	yamlParser "github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+)
+
+type decoder struct {
+	yamlDecoder yamlParser.NodeDecoder
+}
+
+func NewDecoder() parser.Decoder {
+	return &decoder{
+		yamlDecoder: yamlParser.NewNodeDecoder(false),
+	}
+}
+
+// NewStrictDecoder creates a decoder that also reports unknown keywords, such as typos of optional keywords
+func NewStrictDecoder() parser.Decoder {
+	return &decoder{
+		yamlDecoder: yamlParser.NewNodeDecoder(true),
+	}
+}
+
+/*
+Decode decodes the JSON definition and stores it in the schema.
+
+The errors are the same of the YAML decoder, wrapping a *yaml.DecodeError with the position of the invalid definition.
+The source file is the name of the definition reader, when it implements the "Name() string" method like *os.File.
+*/
+func (d *decoder) Decode(definition io.Reader, schema parser.SchemaStorager) error {
+	var file string
+	if named, is := definition.(interface{ Name() string }); is {
+		file = named.Name()
+	}
+
+	content, err := io.ReadAll(definition)
+	if err != nil {
+		return &yamlParser.DecodeError{
+			File: file,
+			Err:  fmt.Errorf("can't read json definition: %w", err),
+		}
+	}
+
+	// The syntax errors of encoding/json have the offset of the invalid character, unlike the errors of its tokens
+	if err := validate(content); err != nil {
+		return newSyntaxError(file, content, err)
+	}
+
+	// The nodes have the same structure of the YAML nodes, so the YAML decoder decodes the definitions
+	root, err := newNodeParser(file, content).parse()
+	if err != nil {
+		return err
+	}
+
+	return d.yamlDecoder.DecodeNode(root, file, schema)
+}
+
+func validate(content []byte) error {
+	var value interface{}
+	return json.Unmarshal(content, &value)
+}
+
+// newSyntaxError returns the syntax error with the line and column of the invalid character, when it is known
+func newSyntaxError(file string, content []byte, err error) error {
+	decodeErr := &yamlParser.DecodeError{
+		File: file,
+		Err:  fmt.Errorf("can't decode json definition: %w", err),
+	}
+
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return decodeErr
+	}
+
+	// The offset is the number of bytes read until the error
+	offset := int(syntaxErr.Offset)
+	if offset > len(content) {
+		offset = len(content)
+	}
+
+	decodeErr.Line = bytes.Count(content[:offset], []byte("\n")) + 1
+	decodeErr.Column = offset - bytes.LastIndexByte(content[:offset], '\n') - 1
+
+	return decodeErr
+}
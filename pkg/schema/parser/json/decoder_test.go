@@ -0,0 +1,190 @@
+package json_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/json"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+)
+
+func TestShouldParseValidJsonDefinition(t *testing.T) {
+	input := strings.NewReader(`{
+  "version": "1.0",
+  "name": "super-cool-service",
+  "events": {
+    "published": {
+      "CAKE_BURNED": {
+        "visibility": "public",
+        "module": "cooker",
+        "attributes": {"$ref": "#/types/Cake"},
+        "entities": {
+          "type": "object",
+          "properties": {
+            "cakeId": {"type": "string", "value": "12354"}
+          }
+        }
+      }
+    },
+    "consumed": {
+      "CAKE_PURCHASED": {"description": "Usado para inciar o processo de fazer o bolo"}
+    }
+  },
+  "types": {
+    "Cake": {
+      "type": "object",
+      "nullable": true,
+      "properties": {
+        "layers": {"type": "integer", "format": "uint8", "value": 5},
+        "shape": {"type": "string", "enum": ["squad", "circle"], "value": "circle"}
+      }
+    }
+  }
+}`)
+
+	resolver := schema.NewBasicResolver()
+	if err := json.NewDecoder().Decode(input, resolver); err != nil {
+		t.Fatal(err)
+	}
+
+	typeDefinitions, err := resolver.GetTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(typeDefinitions) != 1 {
+		t.Fatalf("expected 1 type, received %d", len(typeDefinitions))
+	}
+
+	cake, is := typeDefinitions[0].(*types.Object)
+	if !is {
+		t.Fatalf("expected object type, received '%T'", typeDefinitions[0])
+	}
+
+	if !cake.Nullable() {
+		t.Error("expected nullable type")
+	}
+
+	layers, is := cake.Properties()[0].(*types.Scalar)
+	if !is {
+		t.Fatalf("expected scalar type, received '%T'", cake.Properties()[0])
+	}
+
+	if layers.Value() != 5 || layers.Format() != "uint8" {
+		t.Errorf("expected integer 5 with uint8 format, received '%v' with '%s' format", layers.Value(), layers.Format())
+	}
+
+	events, err := resolver.GetPublishedEvents()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 1 || events[0].Module() != "cooker" || events[0].Visibility() != types.EventPublic {
+		t.Errorf("unexpected published events %v", events)
+	}
+
+	consumedEvents, err := resolver.GetConsumedEvents()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(consumedEvents) != 1 || consumedEvents[0].Name() != "CAKE_PURCHASED" {
+		t.Errorf("unexpected consumed events %v", consumedEvents)
+	}
+}
+
+func TestShouldDecodeJsonStringEscapes(t *testing.T) {
+	input := strings.NewReader(`{
+  "version": "1.0",
+  "name": "super-cool-service",
+  "events": {
+    "consumed": {
+      "CAKE_PURCHASED": {"description": "Usado para inciar o processo de fazer o bolo de chocolate\/morango \u00e9 \"caseiro\""}
+    }
+  }
+}`)
+
+	resolver := schema.NewBasicResolver()
+	if err := json.NewDecoder().Decode(input, resolver); err != nil {
+		t.Fatal(err)
+	}
+
+	consumedEvents, err := resolver.GetConsumedEvents()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `Usado para inciar o processo de fazer o bolo de chocolate/morango é "caseiro"`
+	if len(consumedEvents) != 1 || consumedEvents[0].Description() != expected {
+		t.Errorf("expected '%s' description, received %v", expected, consumedEvents)
+	}
+}
+
+func TestShouldReturnDecodeErrorWithPositionInJsonDefinition(t *testing.T) {
+	testCases := map[string]struct {
+		definition string
+		expected   yaml.DecodeError
+	}{
+		"syntax error": {
+			definition: `{
+  "version": "1.0",
+  "name": "position-service",
+}`,
+			expected: yaml.DecodeError{Line: 4, Column: 1},
+		},
+		"invalid definition": {
+			definition: `{
+  "version": "1.0",
+  "name": "position-service",
+  "types": {
+    "Cake": {"type": "cake"}
+  }
+}`,
+			expected: yaml.DecodeError{Line: 5, Column: 22, Path: "#/types/Cake/type"},
+		},
+		"invalid definition after multi-byte characters": {
+			definition: `{
+  "version": "1.0",
+  "name": "position-service",
+  "types": {"Bolo\u00e9": {"description": "Bolo é", "type": "cake"}}
+}`,
+			expected: yaml.DecodeError{Line: 4, Column: 61, Path: "#/types/Boloé/type"},
+		},
+		"duplicated key": {
+			definition: `{
+  "version": "1.0",
+  "name": "position-service",
+  "name": "other-service"
+}`,
+			expected: yaml.DecodeError{Line: 4, Column: 3},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := json.NewDecoder().Decode(strings.NewReader(testCase.definition), schema.NewBasicResolver())
+
+			var decodeErr *yaml.DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("expected decode error, received '%v'", err)
+			}
+
+			if decodeErr.Line != testCase.expected.Line || decodeErr.Column != testCase.expected.Column {
+				t.Errorf(
+					"expected position %d:%d, received %d:%d",
+					testCase.expected.Line,
+					testCase.expected.Column,
+					decodeErr.Line,
+					decodeErr.Column,
+				)
+			}
+
+			if decodeErr.Path != testCase.expected.Path {
+				t.Errorf("expected '%s' path, received '%s'", testCase.expected.Path, decodeErr.Path)
+			}
+		})
+	}
+}
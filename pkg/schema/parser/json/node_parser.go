@@ -0,0 +1,182 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	yamlParser "github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+	"gopkg.in/yaml.v3"
+)
+
+// nodeParser parses the JSON content to YAML nodes, with the line and column of each value like the YAML parser
+type nodeParser struct {
+	file    string
+	content []byte
+	decoder *json.Decoder
+
+	// offset is the position of the last parsed value, in the line "line" started at the offset "lineStart"
+	offset    int
+	line      int
+	lineStart int
+}
+
+func newNodeParser(file string, content []byte) *nodeParser {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	// The numbers are kept as declared, the YAML decoder converts them to the type of the definition
+	decoder.UseNumber()
+
+	return &nodeParser{
+		file:    file,
+		content: content,
+		decoder: decoder,
+		line:    1,
+	}
+}
+
+// parse returns the document node of the content, which must be valid JSON
+func (p *nodeParser) parse() (*yaml.Node, error) {
+	valueNode, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	return &yaml.Node{
+		Kind:    yaml.DocumentNode,
+		Line:    valueNode.Line,
+		Column:  valueNode.Column,
+		Content: []*yaml.Node{valueNode},
+	}, nil
+}
+
+func (p *nodeParser) parseValue() (*yaml.Node, error) {
+	line, column := p.position()
+
+	token, err := p.decoder.Token()
+	if err != nil {
+		return nil, p.newError(line, column, err)
+	}
+
+	node := &yaml.Node{
+		Kind:   yaml.ScalarNode,
+		Line:   line,
+		Column: column,
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		if token == '[' {
+			return p.parseArray(node)
+		}
+
+		return p.parseObject(node)
+	case string:
+		node.Tag, node.Value = "!!str", token
+	case json.Number:
+		node.Tag, node.Value = numberTag(token), token.String()
+	case bool:
+		node.Tag, node.Value = "!!bool", strconv.FormatBool(token)
+	case nil:
+		node.Tag, node.Value = "!!null", "null"
+	}
+
+	return node, nil
+}
+
+func (p *nodeParser) parseArray(node *yaml.Node) (*yaml.Node, error) {
+	node.Kind, node.Tag = yaml.SequenceNode, "!!seq"
+
+	for p.decoder.More() {
+		itemNode, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		node.Content = append(node.Content, itemNode)
+	}
+
+	return node, p.parseEnd()
+}
+
+func (p *nodeParser) parseObject(node *yaml.Node) (*yaml.Node, error) {
+	node.Kind, node.Tag = yaml.MappingNode, "!!map"
+
+	// encoding/json accepts duplicated keys, which are ambiguous in the definitions and rejected by the YAML parser
+	keyNodes := make(map[string]*yaml.Node)
+
+	for p.decoder.More() {
+		keyNode, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		if definedNode, exists := keyNodes[keyNode.Value]; exists {
+			return nil, p.newError(
+				keyNode.Line,
+				keyNode.Column,
+				fmt.Errorf("key \"%s\" already defined at line %d", keyNode.Value, definedNode.Line),
+			)
+		}
+
+		keyNodes[keyNode.Value] = keyNode
+
+		valueNode, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		node.Content = append(node.Content, keyNode, valueNode)
+	}
+
+	return node, p.parseEnd()
+}
+
+// parseEnd parses the delimiter of the end of the array or object
+func (p *nodeParser) parseEnd() error {
+	line, column := p.position()
+
+	if _, err := p.decoder.Token(); err != nil {
+		return p.newError(line, column, err)
+	}
+
+	return nil
+}
+
+// position returns the line and column of the next value, skipping the whitespaces and separators before it
+func (p *nodeParser) position() (int, int) {
+	start := int(p.decoder.InputOffset())
+	for start < len(p.content) && strings.IndexByte(" \t\r\n,:", p.content[start]) >= 0 {
+		start++
+	}
+
+	for ; p.offset < start; p.offset++ {
+		if p.content[p.offset] == '\n' {
+			p.line++
+			p.lineStart = p.offset + 1
+		}
+	}
+
+	// The columns are counted in characters like the YAML parser
+	return p.line, utf8.RuneCount(p.content[p.lineStart:start]) + 1
+}
+
+func (p *nodeParser) newError(line, column int, err error) error {
+	return &yamlParser.DecodeError{
+		File:   p.file,
+		Line:   line,
+		Column: column,
+		Err:    fmt.Errorf("can't decode json definition: %w", err),
+	}
+}
+
+// numberTag returns the YAML tag of the number, the numbers without fraction or exponent are integers
+func numberTag(number json.Number) string {
+	if strings.ContainsAny(number.String(), ".eE") {
+		return "!!float"
+	}
+
+	return "!!int"
+}
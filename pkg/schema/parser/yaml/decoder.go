@@ -1,109 +1,253 @@
 package yaml
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"regexp"
+	"strconv"
 
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/multierror"
 	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/internal/yamlnode"
 	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
-type decoder struct{}
+var (
+	syntaxErrorLineRegexp = regexp.MustCompile(`^yaml: line (\d+):`)
+)
+
+var (
+	projectKeywords         = []string{"version", "name", "confluence", "events", "types"}
+	confluenceKeywords      = []string{"pages"}
+	confluencePageKeywords  = []string{"title", "spaceKey", "ancestorId"}
+	eventsKeywords          = []string{"published", "consumed"}
+	publishedEventKeywords  = []string{"visibility", "module", "description", "attributes", "entities"}
+	consumedEventKeywords   = []string{"description"}
+	typeCommonKeywords      = []string{"type", "description", "nullable", "$ref"}
+	scalarTypeKeywords      = append([]string{"value", "enum", "format"}, typeCommonKeywords...)
+	arrayTypeKeywords       = append([]string{"items"}, typeCommonKeywords...)
+	objectTypeKeywords      = append([]string{"properties"}, typeCommonKeywords...)
+	typeKeywordsByTypeValue = map[string][]string{
+		"integer": scalarTypeKeywords,
+		"number":  scalarTypeKeywords,
+		"string":  scalarTypeKeywords,
+		"boolean": scalarTypeKeywords,
+		"array":   arrayTypeKeywords,
+		"object":  objectTypeKeywords,
+	}
+)
+
+type decoder struct {
+	strict bool
+}
 
 func NewDecoder() parser.Decoder {
 	return &decoder{}
 }
 
+// NewStrictDecoder creates a decoder that also reports unknown keywords, such as typos of optional keywords
+func NewStrictDecoder() parser.Decoder {
+	return &decoder{
+		strict: true,
+	}
+}
+
+/*
+NodeDecoder decodes the definitions already parsed to YAML nodes, allowing the decoders of formats with the same
+structure, like JSON, to report the errors with the positions of their own parser
+*/
+type NodeDecoder interface {
+	parser.Decoder
+	DecodeNode(root *yaml.Node, file string, schema parser.SchemaStorager) error
+}
+
+// NewNodeDecoder creates a decoder of YAML nodes, which also reports unknown keywords when strict
+func NewNodeDecoder(strict bool) NodeDecoder {
+	return &decoder{
+		strict: strict,
+	}
+}
+
+/*
+Decode decodes the YAML definition and stores it in the schema.
+
+Every invalid definition is reported, grouped in a *multierror.Error. Each error wraps a *DecodeError with the
+position of the invalid definition. The source file is the name of the definition reader, when it implements
+the "Name() string" method like *os.File.
+*/
 func (d *decoder) Decode(definition io.Reader, schema parser.SchemaStorager) error {
-	var project *project
-	if err := yaml.NewDecoder(definition).Decode(&project); err != nil {
-		return fmt.Errorf("can't decode yaml definition: %w", err)
+	var file string
+	if named, is := definition.(interface{ Name() string }); is {
+		file = named.Name()
 	}
 
-	if project.Version != "1.0" {
-		return fmt.Errorf("unsupported '%s' version", project.Version)
+	var root yaml.Node
+	if err := yaml.NewDecoder(definition).Decode(&root); err != nil {
+		return d.newDocument(file, schema).newSyntaxError(err)
 	}
 
-	if err := schema.SetProject(project.Name); err != nil {
-		return err
+	return d.DecodeNode(&root, file, schema)
+}
+
+// DecodeNode decodes the definition already parsed to the root node, reporting "file" as the source file of the errors
+func (d *decoder) DecodeNode(root *yaml.Node, file string, schema parser.SchemaStorager) error {
+	return d.newDocument(file, schema).decode(root)
+}
+
+func (d *decoder) newDocument(file string, schema parser.SchemaStorager) *document {
+	doc := &document{
+		file:   file,
+		schema: schema,
+		strict: d.strict,
 	}
+	doc.NewError = doc.newError
 
-	if err := d.parseConfluence(project, schema); err != nil {
-		return err
+	return doc
+}
+
+// document decodes the nodes of a single YAML definition
+type document struct {
+	yamlnode.Fields
+
+	file   string
+	schema parser.SchemaStorager
+	strict bool
+}
+
+func (d *document) decode(root *yaml.Node) error {
+	projectNode := yamlnode.ResolveAlias(root)
+	if projectNode.Kind == yaml.DocumentNode && len(projectNode.Content) > 0 {
+		projectNode = yamlnode.ResolveAlias(projectNode.Content[0])
 	}
 
-	if err := d.parseTypes(project, schema); err != nil {
-		return err
+	if projectNode.Kind != yaml.MappingNode {
+		return d.newError(projectNode, "#", errors.New("unexpected structure"))
 	}
 
-	if err := d.parsePublishedEvents(project, schema); err != nil {
-		return err
+	// The errors are collected to report every invalid definition at once
+	errs := d.checkKeywords(projectNode, "#", projectKeywords)
+
+	version, err := d.StringField(projectNode, "#", "version")
+	if err != nil {
+		errs = multierror.Append(errs, err)
+	} else if version != "1.0" {
+		errs = multierror.Append(
+			errs,
+			d.newError(yamlnode.FieldOrParent(projectNode, "version"), "#/version", fmt.Errorf("unsupported '%s' version", version)),
+		)
 	}
 
-	if err := d.parseConsumedEvents(project, schema); err != nil {
-		return err
+	name, err := d.StringField(projectNode, "#", "name")
+	if err != nil {
+		return multierror.Append(errs, err)
 	}
 
-	return nil
+	// The other definitions can't be stored without a project
+	if err := d.schema.SetProject(name); err != nil {
+		return multierror.Append(errs, d.newError(yamlnode.FieldOrParent(projectNode, "name"), "#/name", err))
+	}
+
+	errs = multierror.Append(errs, d.parseConfluence(projectNode), d.parseTypes(projectNode))
+
+	eventsNode, err := d.MappingField(projectNode, "#", "events")
+	if err != nil {
+		return multierror.Append(errs, err)
+	}
+
+	return multierror.Append(
+		errs,
+		d.checkKeywords(eventsNode, "#/events", eventsKeywords),
+		d.parsePublishedEvents(eventsNode),
+		d.parseConsumedEvents(eventsNode),
+	)
 }
 
-func (d *decoder) parseConfluence(project *project, schema parser.SchemaStorager) error {
-	for i := range project.Confluence.Pages {
-		err := schema.AddConfluencePage(
-			project.Confluence.Pages[i].Title,
-			project.Confluence.Pages[i].SpaceKey,
-			project.Confluence.Pages[i].AncestorID,
-		)
+func (d *document) parseConfluence(projectNode *yaml.Node) error {
+	confluenceNode, err := d.MappingField(projectNode, "#", "confluence")
+	if err != nil {
+		return err
+	}
 
-		if err != nil {
-			return addPathToError("#/confluence/pages", fmt.Errorf("can't add page at '%d' index: %w", i, err))
+	errs := d.checkKeywords(confluenceNode, "#/confluence", confluenceKeywords)
+
+	pagesNode, err := d.SequenceField(confluenceNode, "#/confluence", "pages")
+	if err != nil {
+		return multierror.Append(errs, err)
+	}
+
+	for i, pageNode := range pagesNode {
+		path := fmt.Sprintf("#/confluence/pages/%d", i)
+
+		pageNode = yamlnode.ResolveAlias(pageNode)
+		if pageNode.Kind != yaml.MappingNode {
+			errs = multierror.Append(errs, d.newError(pageNode, path, errors.New("unexpected structure")))
+			continue
+		}
+
+		keywordsErr := d.checkKeywords(pageNode, path, confluencePageKeywords)
+		title, titleErr := d.StringField(pageNode, path, "title")
+		spaceKey, spaceKeyErr := d.StringField(pageNode, path, "spaceKey")
+		ancestorID, ancestorIDErr := d.StringField(pageNode, path, "ancestorId")
+
+		if err := multierror.Append(keywordsErr, titleErr, spaceKeyErr, ancestorIDErr); err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		if err := d.schema.AddConfluencePage(title, spaceKey, ancestorID); err != nil {
+			errs = multierror.Append(errs, d.newError(pageNode, path, fmt.Errorf("can't add page: %w", err)))
 		}
 	}
 
-	return nil
+	return errs
 }
 
-func (d *decoder) parseTypes(project *project, schema parser.SchemaStorager) error {
-	types, err := d.parseTypeDefinitions("#/types", project.Types)
+func (d *document) parseTypes(projectNode *yaml.Node) error {
+	typesNode, err := d.MappingField(projectNode, "#", "types")
 	if err != nil {
-		return fmt.Errorf("can't parse types: %w", err)
+		return err
 	}
 
+	// The valid types are registered even when other types are invalid
+	types, errs := d.parseTypeDefinitions("#/types", typesNode)
+
 	for i := range types {
-		if err := schema.AddType(types[i]); err != nil {
-			return fmt.Errorf("can't register type: %w", err)
+		if err := d.schema.AddType(types[i]); err != nil {
+			errs = multierror.Append(errs, d.newError(typesNode, types[i].Path(), fmt.Errorf("can't register type: %w", err)))
 		}
 	}
 
-	return nil
+	return errs
 }
 
-func (d *decoder) parsePublishedEvents(project *project, schema parser.SchemaStorager) error {
-	for i := range project.Events.Published {
-		name, path := d.yamlMapItemToNameAndPath("#/events/published", project.Events.Published[i])
+func (d *document) parsePublishedEvents(eventsNode *yaml.Node) error {
+	publishedNode, err := d.MappingField(eventsNode, "#/events", "published")
+	if err != nil {
+		return err
+	}
 
-		rawEventDefinition, err := d.yamlMapItemValueToMap(path, project.Events.Published[i].Value)
-		if err != nil {
-			return err
-		}
+	var errs error
 
-		visibility, err := d.parseEventVisibility(path, rawEventDefinition)
-		if err != nil {
-			return err
-		}
-
-		module, _ := rawEventDefinition["module"].(string)
-		description, _ := rawEventDefinition["description"].(string)
+	for _, item := range yamlnode.MappingItems(publishedNode) {
+		name, path := item.Key.Value, fmt.Sprintf("#/events/published/%s", item.Key.Value)
 
-		attributesType, err := d.parserEventTypeDefinition(path, "attributes", rawEventDefinition)
-		if err != nil {
-			return err
+		if item.Value.Kind != yaml.MappingNode {
+			errs = multierror.Append(errs, d.newError(item.Value, path, errors.New("unexpected structure")))
+			continue
 		}
 
-		entitiesType, err := d.parserEventTypeDefinition(path, "entities", rawEventDefinition)
-		if err != nil {
-			return err
+		keywordsErr := d.checkKeywords(item.Value, path, publishedEventKeywords)
+		visibility, visibilityErr := d.parseEventVisibility(path, item.Value)
+		module, moduleErr := d.StringField(item.Value, path, "module")
+		description, descriptionErr := d.StringField(item.Value, path, "description")
+		attributesType, attributesErr := d.parserEventTypeDefinition(path, "attributes", item.Value)
+		entitiesType, entitiesErr := d.parserEventTypeDefinition(path, "entities", item.Value)
+
+		if err := multierror.Append(keywordsErr, visibilityErr, moduleErr, descriptionErr, attributesErr, entitiesErr); err != nil {
+			errs = multierror.Append(errs, err)
+			continue
 		}
 
 		event, err := types.NewPublishdEvent(
@@ -115,166 +259,164 @@ func (d *decoder) parsePublishedEvents(project *project, schema parser.SchemaSto
 			entitiesType,
 		)
 		if err != nil {
-			return addPathToError(path, err)
+			errs = multierror.Append(errs, d.newError(item.Key, path, err))
+			continue
 		}
 
-		if err := schema.AddPublishedEvent(event); err != nil {
-			return fmt.Errorf("can't register published event: %w", err)
+		if err := d.schema.AddPublishedEvent(event); err != nil {
+			errs = multierror.Append(errs, d.newError(item.Key, path, fmt.Errorf("can't register published event: %w", err)))
 		}
 	}
 
-	return nil
+	return errs
 }
 
-func (d *decoder) parseConsumedEvents(project *project, schema parser.SchemaStorager) error {
-	for i := range project.Events.Consumed {
-		name, path := d.yamlMapItemToNameAndPath("#/events/consumed", project.Events.Consumed[i])
+func (d *document) parseConsumedEvents(eventsNode *yaml.Node) error {
+	consumedNode, err := d.MappingField(eventsNode, "#/events", "consumed")
+	if err != nil {
+		return err
+	}
+
+	var errs error
 
-		rawEventDefinition, err := d.yamlMapItemValueToMap(path, project.Events.Consumed[i].Value)
-		if err != nil {
-			return err
+	for _, item := range yamlnode.MappingItems(consumedNode) {
+		name, path := item.Key.Value, fmt.Sprintf("#/events/consumed/%s", item.Key.Value)
+
+		if item.Value.Kind != yaml.MappingNode {
+			errs = multierror.Append(errs, d.newError(item.Value, path, errors.New("unexpected structure")))
+			continue
 		}
 
-		description, _ := rawEventDefinition["description"].(string)
+		keywordsErr := d.checkKeywords(item.Value, path, consumedEventKeywords)
+		description, descriptionErr := d.StringField(item.Value, path, "description")
+
+		if err := multierror.Append(keywordsErr, descriptionErr); err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
 
 		event, err := types.NewConsumedEvent(name, description)
 		if err != nil {
-			return addPathToError(path, err)
+			errs = multierror.Append(errs, d.newError(item.Key, path, err))
+			continue
 		}
 
-		if err := schema.AddConsumedEvent(event); err != nil {
-			return fmt.Errorf("can't register consumed event: %w", err)
+		if err := d.schema.AddConsumedEvent(event); err != nil {
+			errs = multierror.Append(errs, d.newError(item.Key, path, fmt.Errorf("can't register consumed event: %w", err)))
 		}
 	}
 
-	return nil
+	return errs
 }
 
-func (d *decoder) parseEventVisibility(path string, eventDefinition map[string]interface{}) (types.EventVisibility, error) {
-	visibility, _ := eventDefinition["visibility"].(string)
+func (d *document) parseEventVisibility(path string, eventNode *yaml.Node) (types.EventVisibility, error) {
+	visibility, err := d.StringField(eventNode, path, "visibility")
+	if err != nil {
+		return types.EventVisibility(0), err
+	}
 
 	v, err := types.NewEventVisibility(visibility)
 	if err != nil {
-		err = addPathToError(path, err)
+		err = d.newError(yamlnode.FieldOrParent(eventNode, "visibility"), path+"/visibility", err)
 	}
 
 	return v, err
 }
 
-func (d *decoder) parserEventTypeDefinition(path, key string, rawEventDefinition map[string]interface{}) (types.TypeDescriber, error) {
-	rawType, err := d.extractYamlMapSliceFromMap(path, key, rawEventDefinition)
-	if err != nil {
-		return nil, err
-	}
-
-	typeDefinition, err := d.parseTypeDefinition(
-		key,
-		fmt.Sprintf("%s/%s", path, key),
-		d.yamlMapSliceToMap(rawType),
-	)
-	if err != nil {
-		return nil, err
+func (d *document) parserEventTypeDefinition(path, key string, eventNode *yaml.Node) (types.TypeDescriber, error) {
+	typeNode := yamlnode.MappingValue(eventNode, key)
+	if typeNode == nil || typeNode.Kind != yaml.MappingNode {
+		return nil, d.newError(yamlnode.FieldOrParent(eventNode, key), fmt.Sprintf("%s/%s", path, key), errors.New("unexpected structure"))
 	}
 
-	return typeDefinition, nil
+	return d.parseTypeDefinition(key, fmt.Sprintf("%s/%s", path, key), typeNode)
 }
 
-func (d *decoder) parseTypeDefinitions(path string, rawTypes yaml.MapSlice) ([]types.TypeDescriber, error) {
-	var typeDefinitions []types.TypeDescriber
+// parseTypeDefinitions returns the valid type definitions and the errors of the invalid ones
+func (d *document) parseTypeDefinitions(path string, typesNode *yaml.Node) ([]types.TypeDescriber, error) {
+	var (
+		typeDefinitions []types.TypeDescriber
+		errs            error
+	)
 
-	for i := range rawTypes {
-		name, path := d.yamlMapItemToNameAndPath(path, rawTypes[i])
+	for _, item := range yamlnode.MappingItems(typesNode) {
+		name, path := item.Key.Value, fmt.Sprintf("%s/%s", path, item.Key.Value)
 
-		rawTypeDefinition, err := d.yamlMapItemValueToMap(path, rawTypes[i].Value)
-		if err != nil {
-			return nil, err
+		if item.Value.Kind != yaml.MappingNode {
+			errs = multierror.Append(errs, d.newError(item.Value, path, errors.New("unexpected structure")))
+			continue
 		}
 
-		typeDefinition, err := d.parseTypeDefinition(name, path, rawTypeDefinition)
+		typeDefinition, err := d.parseTypeDefinition(name, path, item.Value)
 		if err != nil {
-			return nil, err
+			errs = multierror.Append(errs, err)
+			continue
 		}
 
 		typeDefinitions = append(typeDefinitions, typeDefinition)
 	}
 
-	return typeDefinitions, nil
-}
-
-func (d *decoder) yamlMapItemToNameAndPath(path string, item yaml.MapItem) (string, string) {
-	name := fmt.Sprint(item.Key)
-	return name, fmt.Sprintf("%s/%s", path, name)
+	return typeDefinitions, errs
 }
 
-func (d *decoder) yamlMapItemValueToMap(path string, value interface{}) (map[string]interface{}, error) {
-	mapSlice, is := value.(yaml.MapSlice)
-	if !is {
-		return nil, fmt.Errorf("%s: unexpected structure", path)
+func (d *document) parseTypeDefinition(name, path string, typeNode *yaml.Node) (types.TypeDescriber, error) {
+	var keywordsErr error
+	if keywords, known := typeKeywords(typeNode); known {
+		keywordsErr = d.checkKeywords(typeNode, path, keywords)
 	}
 
-	return d.yamlMapSliceToMap(mapSlice), nil
-}
-
-func (d *decoder) extractYamlMapSliceFromMap(path, key string, m map[string]interface{}) (yaml.MapSlice, error) {
-	mapSlice, is := m[key].(yaml.MapSlice)
-	if !is {
-		return nil, fmt.Errorf("%s/%s: unexpected structure", path, key)
+	typeDefinition, err := d.parseTypeKeywords(name, path, typeNode)
+	if err := multierror.Append(keywordsErr, err); err != nil {
+		return nil, err
 	}
 
-	return mapSlice, nil
+	return typeDefinition, nil
 }
 
-func (*decoder) yamlMapSliceToMap(mapSlice yaml.MapSlice) map[string]interface{} {
-	result := make(map[string]interface{})
-	for i := range mapSlice {
-		result[fmt.Sprint(mapSlice[i].Key)] = mapSlice[i].Value
-	}
+func (d *document) parseTypeKeywords(name, path string, typeNode *yaml.Node) (types.TypeDescriber, error) {
+	description, descriptionErr := d.StringField(typeNode, path, "description")
+	nullable, nullableErr := d.boolField(typeNode, path, "nullable")
 
-	return result
-}
+	if err := multierror.Append(descriptionErr, nullableErr); err != nil {
+		return nil, err
+	}
 
-func (d *decoder) parseTypeDefinition(
-	name, path string,
-	typeDefinition map[string]interface{},
-) (types.TypeDescriber, error) {
-	description, _ := typeDefinition["description"].(string)
-	nullable, _ := typeDefinition["nullable"].(bool)
+	if referenceNode := yamlnode.MappingValue(typeNode, "$ref"); referenceNode != nil {
+		reference, err := d.StringField(typeNode, path, "$ref")
+		if err != nil {
+			return nil, err
+		}
 
-	reference, isReference := typeDefinition["$ref"].(string)
-	if isReference {
 		referenceType, err := types.NewReference(name, path, description, nullable, reference)
 		if err != nil {
-			err = addPathToError(path, err)
+			return nil, d.newError(referenceNode, path+"/$ref", err)
 		}
 
-		return referenceType, err
+		d.setPosition(path, referenceNode)
+		return referenceType, nil
 	}
 
-	typeKeyword, isTypeKeywordValid := typeDefinition["type"].(string)
-	if !isTypeKeywordValid {
-		return nil, fmt.Errorf("%s/type: invalid type identifier declaration", path)
+	typeKeywordNode := yamlnode.MappingValue(typeNode, "type")
+	if typeKeywordNode == nil || typeKeywordNode.Kind != yaml.ScalarNode || typeKeywordNode.Tag != "!!str" {
+		return nil, d.newError(yamlnode.FieldOrParent(typeNode, "type"), path+"/type", errors.New("invalid type identifier declaration"))
 	}
 
-	switch typeKeyword {
+	switch typeKeyword := typeKeywordNode.Value; typeKeyword {
 	case "integer":
-		return parseScalarType[int](name, path, description, typeKeyword, nullable, typeDefinition)
+		return parseScalarType[int](d, name, path, description, typeKeyword, nullable, typeNode)
 	case "number":
-		return parseScalarType[float64](name, path, description, typeKeyword, nullable, typeDefinition)
+		return parseScalarType[float64](d, name, path, description, typeKeyword, nullable, typeNode)
 	case "string":
-		return parseScalarType[string](name, path, description, typeKeyword, nullable, typeDefinition)
+		return parseScalarType[string](d, name, path, description, typeKeyword, nullable, typeNode)
 	case "boolean":
-		return parseScalarType[bool](name, path, description, typeKeyword, nullable, typeDefinition)
+		return parseScalarType[bool](d, name, path, description, typeKeyword, nullable, typeNode)
 	case "array":
-		rawItems, err := d.extractYamlMapSliceFromMap(path, "items", typeDefinition)
-		if err != nil {
-			return nil, err
+		itemsNode := yamlnode.MappingValue(typeNode, "items")
+		if itemsNode == nil || itemsNode.Kind != yaml.MappingNode {
+			return nil, d.newError(yamlnode.FieldOrParent(typeNode, "items"), path+"/items", errors.New("unexpected structure"))
 		}
 
-		itemsType, err := d.parseTypeDefinition(
-			"items",
-			fmt.Sprintf("%s/items", path),
-			d.yamlMapSliceToMap(rawItems),
-		)
+		itemsType, err := d.parseTypeDefinition("items", path+"/items", itemsNode)
 		if err != nil {
 			return nil, err
 		}
@@ -287,17 +429,18 @@ func (d *decoder) parseTypeDefinition(
 			itemsType,
 		)
 		if err != nil {
-			return nil, addPathToError(path, err)
+			return nil, d.newError(typeNode, path, err)
 		}
 
 		return arrayType, nil
 	case "object":
-		rawProperties, err := d.extractYamlMapSliceFromMap(path, "properties", typeDefinition)
-		if err != nil {
-			return nil, err
+		propertiesNode := yamlnode.MappingValue(typeNode, "properties")
+		if propertiesNode == nil || propertiesNode.Kind != yaml.MappingNode {
+			return nil, d.newError(yamlnode.FieldOrParent(typeNode, "properties"), path+"/properties", errors.New("unexpected structure"))
 		}
 
-		typeDefinitions, err := d.parseTypeDefinitions(fmt.Sprintf("%s/properties", path), rawProperties)
+		// An invalid property invalidates the object, but all invalid properties are reported
+		typeDefinitions, err := d.parseTypeDefinitions(path+"/properties", propertiesNode)
 		if err != nil {
 			return nil, err
 		}
@@ -310,26 +453,26 @@ func (d *decoder) parseTypeDefinition(
 			typeDefinitions,
 		)
 		if err != nil {
-			return nil, addPathToError(path, err)
+			return nil, d.newError(typeNode, path, err)
 		}
 
 		return objectType, nil
 	default:
-		return nil, fmt.Errorf("%s/type: '%s' not supported", path, typeKeyword)
+		return nil, d.newError(typeKeywordNode, path+"/type", fmt.Errorf("'%s' not supported", typeKeyword))
 	}
 }
 
-func addPathToError(path string, err error) error {
-	return fmt.Errorf("%s: %w", path, err)
-}
-
 func parseScalarType[T scalar](
+	d *document,
 	name, path, description, typeKeyword string,
 	nullable bool,
-	typeDefinition map[string]interface{},
+	typeNode *yaml.Node,
 ) (types.TypeDescriber, error) {
-	value, err := parserScalarValue[T](path, nullable, typeDefinition)
-	if err != nil {
+	value, valueErr := parserScalarValue[T](d, path, nullable, typeNode)
+	enumValues, enumErr := parserScalarEnum[T](d, path, nullable, typeNode)
+	format, formatErr := d.StringField(typeNode, path, "format")
+
+	if err := multierror.Append(valueErr, enumErr, formatErr); err != nil {
 		return nil, err
 	}
 
@@ -341,13 +484,6 @@ func parseScalarType[T scalar](
 		rawValue = nil
 	}
 
-	enumValues, err := parserScalarEnum[T](path, nullable, typeDefinition)
-	if err != nil {
-		return nil, err
-	}
-
-	format, _ := typeDefinition["format"].(string)
-
 	scalarType, err := types.NewScalar(
 		name,
 		path,
@@ -360,36 +496,58 @@ func parseScalarType[T scalar](
 	)
 
 	if err != nil {
-		return nil, addPathToError(path, err)
+		return nil, d.newError(typeNode, path, err)
 	}
 
 	return scalarType, nil
 }
 
-func parserScalarValue[T scalar](path string, nullable bool, typeDefinition map[string]interface{}) (*T, error) {
-	if nullable && typeDefinition["value"] == nil {
+func parserScalarValue[T scalar](d *document, path string, nullable bool, typeNode *yaml.Node) (*T, error) {
+	valueNode := yamlnode.MappingValue(typeNode, "value")
+
+	var rawValue interface{}
+	if valueNode != nil {
+		if err := valueNode.Decode(&rawValue); err != nil {
+			return nil, d.newError(valueNode, path+"/value", err)
+		}
+	}
+
+	if nullable && rawValue == nil {
 		return nil, nil
 	}
 
-	value, is := typeDefinition["value"].(T)
+	value, is := rawValue.(T)
 	if !is {
-		return nil, fmt.Errorf("%s/value: is not of type '%T'", path, value)
+		return nil, d.newError(yamlnode.FieldOrParent(typeNode, "value"), path+"/value", fmt.Errorf("is not of type '%T'", value))
 	}
 
 	return &value, nil
 }
 
-func parserScalarEnum[T scalar](path string, nullable bool, typeDefinition map[string]interface{}) ([]interface{}, error) {
-	enumValues, _ := typeDefinition["enum"].([]interface{})
+func parserScalarEnum[T scalar](d *document, path string, nullable bool, typeNode *yaml.Node) ([]interface{}, error) {
+	enumNode := yamlnode.MappingValue(typeNode, "enum")
+	if enumNode == nil || yamlnode.IsNull(enumNode) {
+		return nil, nil
+	}
+
+	if enumNode.Kind != yaml.SequenceNode {
+		return nil, d.newError(enumNode, path+"/enum", errors.New("unexpected structure"))
+	}
+
+	enumValues := make([]interface{}, len(enumNode.Content))
+
+	for i := range enumNode.Content {
+		if err := enumNode.Content[i].Decode(&enumValues[i]); err != nil {
+			return nil, d.newError(enumNode.Content[i], path+"/enum", err)
+		}
 
-	for i := range enumValues {
 		if nullable && enumValues[i] == nil {
 			continue
 		}
 
 		_, is := enumValues[i].(T)
 		if !is {
-			return nil, fmt.Errorf("%s/enum: invalid enum type at %d position", path, i)
+			return nil, d.newError(enumNode.Content[i], path+"/enum", fmt.Errorf("invalid enum type at %d position", i))
 		}
 	}
 
@@ -399,3 +557,102 @@ func parserScalarEnum[T scalar](path string, nullable bool, typeDefinition map[s
 type scalar interface {
 	int | float64 | string | bool
 }
+
+// typeKeywords returns the keywords allowed in the type definition, which depend on its type, and whether they are known
+func typeKeywords(typeNode *yaml.Node) ([]string, bool) {
+	if yamlnode.MappingValue(typeNode, "$ref") != nil {
+		return typeCommonKeywords, true
+	}
+
+	if typeKeywordNode := yamlnode.MappingValue(typeNode, "type"); typeKeywordNode != nil {
+		// The keywords of unknown types are unknown, only the unknown type is reported
+		keywords, exists := typeKeywordsByTypeValue[typeKeywordNode.Value]
+		return keywords, exists
+	}
+
+	return typeCommonKeywords, true
+}
+
+// checkKeywords reports the keys of the mapping node that are not in "keywords". Does nothing when the decoding is not strict
+func (d *document) checkKeywords(mapping *yaml.Node, path string, keywords []string) error {
+	if !d.strict {
+		return nil
+	}
+
+	var errs error
+
+	for _, item := range yamlnode.MappingItems(mapping) {
+		if !containsKeyword(keywords, item.Key.Value) {
+			errs = multierror.Append(
+				errs,
+				d.newError(item.Key, fmt.Sprintf("%s/%s", path, item.Key.Value), fmt.Errorf("unknown keyword '%s'", item.Key.Value)),
+			)
+		}
+	}
+
+	return errs
+}
+
+func containsKeyword(keywords []string, keyword string) bool {
+	for i := range keywords {
+		if keywords[i] == keyword {
+			return true
+		}
+	}
+
+	return false
+}
+
+// boolField returns the boolean value of "key" or false when it doesn't exist
+func (d *document) boolField(mapping *yaml.Node, path, key string) (bool, error) {
+	node := yamlnode.MappingValue(mapping, key)
+	if node == nil || yamlnode.IsNull(node) {
+		return false, nil
+	}
+
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+		return false, d.newError(node, fmt.Sprintf("%s/%s", path, key), errors.New("must be a boolean"))
+	}
+
+	value, err := strconv.ParseBool(node.Value)
+	if err != nil {
+		return false, d.newError(node, fmt.Sprintf("%s/%s", path, key), errors.New("must be a boolean"))
+	}
+
+	return value, nil
+}
+
+func (d *document) newError(node *yaml.Node, path string, err error) error {
+	decodeErr := &DecodeError{
+		File: d.file,
+		Path: path,
+		Err:  err,
+	}
+
+	if node != nil {
+		decodeErr.Line = node.Line
+		decodeErr.Column = node.Column
+	}
+
+	return decodeErr
+}
+
+// setPosition stores the position of the reference in the schemas that report it in the resolution errors
+func (d *document) setPosition(path string, referenceNode *yaml.Node) {
+	if storager, is := d.schema.(parser.PositionStorager); is {
+		storager.SetPosition(path, referenceNode.Line, referenceNode.Column)
+	}
+}
+
+func (d *document) newSyntaxError(err error) error {
+	decodeErr := &DecodeError{
+		File: d.file,
+		Err:  fmt.Errorf("can't decode yaml definition: %w", err),
+	}
+
+	if matches := syntaxErrorLineRegexp.FindStringSubmatch(err.Error()); matches != nil {
+		decodeErr.Line, _ = strconv.Atoi(matches[1])
+	}
+
+	return decodeErr
+}
@@ -0,0 +1,48 @@
+package yaml
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DecodeError records an error and the position of the definition that caused it
+type DecodeError struct {
+	// File is the source file of the definition. Empty when the reader doesn't have a name
+	File string
+	// Line and Column start at 1. Zero when the position is unknown
+	Line   int
+	Column int
+	// Path of the definition using the "#/events/published/EVENT_NAME/attributes" format.
+	// Empty when the error is not related to a definition, such as syntax errors
+	Path string
+	Err  error
+}
+
+// Error returns the error in the "file:line:column: path: message" format, omitting the unknown parts
+func (e *DecodeError) Error() string {
+	var parts []string
+
+	location := e.File
+	if e.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, e.Line)
+
+		if e.Column > 0 {
+			location = fmt.Sprintf("%s:%d", location, e.Column)
+		}
+	}
+
+	if len(location) > 0 {
+		parts = append(parts, strings.TrimPrefix(location, ":"))
+	}
+
+	if len(e.Path) > 0 {
+		parts = append(parts, e.Path)
+	}
+
+	parts = append(parts, e.Err.Error())
+	return strings.Join(parts, ": ")
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,281 @@
+package yaml_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/multierror"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+)
+
+func TestShouldReturnDecodeErrorWithPositionOfInvalidDefinition(t *testing.T) {
+	testCases := map[string]struct {
+		definition string
+		expected   yaml.DecodeError
+	}{
+		"unsupported type": {
+			definition: `version: "1.0"
+name: position-service
+types:
+  Cake:
+    type: cake
+`,
+			expected: yaml.DecodeError{Line: 5, Column: 11, Path: "#/types/Cake/type"},
+		},
+		"missing type keyword points to the definition": {
+			definition: `version: "1.0"
+name: position-service
+types:
+  Cake:
+    description: Representa um bolo
+`,
+			expected: yaml.DecodeError{Line: 5, Column: 5, Path: "#/types/Cake/type"},
+		},
+		"invalid visibility": {
+			definition: `version: "1.0"
+name: position-service
+events:
+  published:
+    CAKE_BURNED:
+      visibility: everyone
+`,
+			expected: yaml.DecodeError{Line: 6, Column: 19, Path: "#/events/published/CAKE_BURNED/visibility"},
+		},
+		"invalid nested value": {
+			definition: `version: "1.0"
+name: position-service
+types:
+  Cake:
+    type: object
+    properties:
+      layers:
+        type: integer
+        value: five
+`,
+			expected: yaml.DecodeError{Line: 9, Column: 16, Path: "#/types/Cake/properties/layers/value"},
+		},
+		"unsupported version": {
+			definition: `version: "2.0"
+name: position-service
+`,
+			expected: yaml.DecodeError{Line: 1, Column: 10, Path: "#/version"},
+		},
+		"syntax error": {
+			definition: `version: "1.0"
+name: position: service
+`,
+			expected: yaml.DecodeError{Line: 2},
+		},
+	}
+
+	for name, testCase := range testCases {
+		err := yaml.NewDecoder().Decode(strings.NewReader(testCase.definition), newSchameStorageSpy())
+		if err == nil {
+			t.Errorf("%s: an error was expected", name)
+			continue
+		}
+
+		var decodeErr *yaml.DecodeError
+		if !errors.As(err, &decodeErr) {
+			t.Errorf("%s: expected a decode error, received '%s'", name, err)
+			continue
+		}
+
+		if decodeErr.Path != testCase.expected.Path {
+			t.Errorf("%s: expected '%s' path, received '%s'", name, testCase.expected.Path, decodeErr.Path)
+		}
+
+		if decodeErr.Line != testCase.expected.Line || decodeErr.Column != testCase.expected.Column {
+			t.Errorf(
+				"%s: expected position %d:%d, received %d:%d",
+				name,
+				testCase.expected.Line,
+				testCase.expected.Column,
+				decodeErr.Line,
+				decodeErr.Column,
+			)
+		}
+	}
+}
+
+func TestShouldAddSourceFileToDecodeError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lifecycle.yaml")
+	if err := os.WriteFile(path, []byte("version: \"1.0\"\nname: file-service\ntypes:\n  Cake:\n    type: cake\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	err = yaml.NewDecoder().Decode(file, newSchameStorageSpy())
+
+	var decodeErr *yaml.DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected a decode error, received '%v'", err)
+	}
+
+	if decodeErr.File != path {
+		t.Errorf("expected '%s' file, received '%s'", path, decodeErr.File)
+	}
+
+	expected := path + ":5:11: #/types/Cake/type: 'cake' not supported"
+	if decodeErr.Error() != expected {
+		t.Errorf("expected '%s' message, received '%s'", expected, decodeErr.Error())
+	}
+}
+
+func TestShouldReportAllInvalidDefinitions(t *testing.T) {
+	definition := `version: "1.0"
+name: many-errors-service
+events:
+  published:
+    CAKE_BURNED:
+      visibility: everyone
+      attributes:
+        type: object
+        properties:
+          id:
+            type: strin
+      entities:
+        type: object
+        properties:
+          id:
+            type: integer
+            value: 1
+types:
+  Cake:
+    type: cake
+  Layers:
+    type: integer
+    value: five
+`
+
+	err := yaml.NewDecoder().Decode(strings.NewReader(definition), newSchameStorageSpy())
+
+	expectedPaths := []string{
+		"#/types/Cake/type",
+		"#/types/Layers/value",
+		"#/events/published/CAKE_BURNED/visibility",
+		"#/events/published/CAKE_BURNED/attributes/properties/id/type",
+	}
+
+	errs := multierror.Flatten(err)
+	if len(errs) != len(expectedPaths) {
+		t.Fatalf("expected %d errors, received %d: %v", len(expectedPaths), len(errs), err)
+	}
+
+	for i := range errs {
+		var decodeErr *yaml.DecodeError
+		if !errors.As(errs[i], &decodeErr) {
+			t.Errorf("expected a decode error, received '%s'", errs[i])
+			continue
+		}
+
+		if decodeErr.Path != expectedPaths[i] {
+			t.Errorf("expected '%s' path, received '%s'", expectedPaths[i], decodeErr.Path)
+		}
+	}
+}
+
+func TestShouldReportOnlyUnknownTypeInStrictMode(t *testing.T) {
+	definition := `version: "1.0"
+name: strict-service
+types:
+  Cake:
+    type: strin
+    value: chocolate
+`
+
+	err := yaml.NewStrictDecoder().Decode(strings.NewReader(definition), newSchameStorageSpy())
+
+	errs := multierror.Flatten(err)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, received %d: %v", len(errs), err)
+	}
+
+	var decodeErr *yaml.DecodeError
+	if !errors.As(errs[0], &decodeErr) || decodeErr.Path != "#/types/Cake/type" {
+		t.Errorf("expected unknown type error at '#/types/Cake/type', received '%s'", errs[0])
+	}
+}
+
+func TestShouldReportUnknownKeywordsInStrictMode(t *testing.T) {
+	definition := `version: "1.0"
+name: strict-service
+owner: squad
+confluence:
+  pages:
+    - spaceKey: SPACE_KEY
+      ancestorId: "1"
+      title: Strict
+      titleSufix: Typo
+events:
+  published:
+    CAKE_BURNED:
+      visibility: public
+      modle: cooker
+      attributes:
+        type: object
+        properties:
+          id:
+            type: integer
+            nulable: true
+            value: 1
+      entities:
+        type: object
+        items:
+          type: string
+        properties:
+          id:
+            $ref: '#/types/Cake'
+            descripton: Bolo
+  consumed:
+    CAKE_PURCHASED:
+      description: Usado para fazer o bolo
+      visibility: public
+types:
+  Cake:
+    type: string
+    value: chocolate
+`
+
+	if err := yaml.NewDecoder().Decode(strings.NewReader(definition), newSchameStorageSpy()); err != nil {
+		t.Fatalf("expected unknown keywords to be ignored, received '%s'", err)
+	}
+
+	err := yaml.NewStrictDecoder().Decode(strings.NewReader(definition), newSchameStorageSpy())
+
+	expectedPaths := []string{
+		"#/owner",
+		"#/confluence/pages/0/titleSufix",
+		"#/events/published/CAKE_BURNED/modle",
+		"#/events/published/CAKE_BURNED/attributes/properties/id/nulable",
+		"#/events/published/CAKE_BURNED/entities/items",
+		"#/events/published/CAKE_BURNED/entities/properties/id/descripton",
+		"#/events/consumed/CAKE_PURCHASED/visibility",
+	}
+
+	errs := multierror.Flatten(err)
+	if len(errs) != len(expectedPaths) {
+		t.Fatalf("expected %d errors, received %d: %v", len(expectedPaths), len(errs), err)
+	}
+
+	for i := range errs {
+		var decodeErr *yaml.DecodeError
+		if !errors.As(errs[i], &decodeErr) {
+			t.Errorf("expected a decode error, received '%s'", errs[i])
+			continue
+		}
+
+		if decodeErr.Path != expectedPaths[i] {
+			t.Errorf("expected '%s' path, received '%s'", expectedPaths[i], decodeErr.Path)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser"
+)
+
+// ReferenceLoader loads the documents of external references, such as "./common.yaml#/types/Money"
+type ReferenceLoader interface {
+	// Load returns the resolver of the "file" referenced in the document "referencingDocument".
+	// The "referencingDocument" is an absolute path or empty when the document has no file
+	Load(referencingDocument, file string) (*BasicResolver, error)
+}
+
+// FileReader reads the content of the file "path", allowing to load the external documents from other sources than the
+// file system, like a git revision
+type FileReader func(path string) ([]byte, error)
+
+type loadedDocument struct {
+	resolver *BasicResolver
+	err      error
+}
+
+// FileReferenceLoader loads the external documents from the file system with the decoder. Each file is loaded only once
+type FileReferenceLoader struct {
+	decoder     parser.Decoder
+	searchPaths []string
+	readFile    FileReader
+
+	// documents stores the loaded documents by absolute path
+	documents map[string]loadedDocument
+}
+
+/*
+Load returns the resolver of the "file". The relative paths are searched in the directory of the "referencingDocument"
+and then in the search paths, in the specified order.
+*/
+func (f *FileReferenceLoader) Load(referencingDocument, file string) (*BasicResolver, error) {
+	path, absPath, content, err := f.findFile(referencingDocument, file)
+	if err != nil {
+		return nil, err
+	}
+
+	document, exists := f.documents[absPath]
+	if !exists {
+		document.resolver, document.err = f.decode(path, absPath, content)
+		f.documents[absPath] = document
+	}
+
+	return document.resolver, document.err
+}
+
+// SetFileReader replaces the reading of the files from the file system by the "readFile"
+func (f *FileReferenceLoader) SetFileReader(readFile FileReader) {
+	f.readFile = readFile
+}
+
+// findFile returns the path of the "file" as found, its absolute path and its content
+func (f *FileReferenceLoader) findFile(referencingDocument, file string) (string, string, []byte, error) {
+	candidates := []string{file}
+
+	if !filepath.IsAbs(file) {
+		candidates = []string{filepath.Join(filepath.Dir(referencingDocument), file)}
+
+		// Relative paths of documents without file are relative to the working directory
+		if len(referencingDocument) < 1 {
+			candidates = []string{filepath.Clean(file)}
+		}
+
+		for i := range f.searchPaths {
+			candidates = append(candidates, filepath.Join(f.searchPaths[i], file))
+		}
+	}
+
+	for _, candidate := range candidates {
+		absPath, err := filepath.Abs(candidate)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("can't resolve path '%s': %w", candidate, err)
+		}
+
+		// The relative path is shorter in the decoding errors
+		path := relativeToWorkingDir(absPath)
+
+		content, err := f.readFile(path)
+		if err != nil {
+			continue
+		}
+
+		return path, absPath, content, nil
+	}
+
+	return "", "", nil, fmt.Errorf("file '%s' not found", file)
+}
+
+func (f *FileReferenceLoader) decode(path, absPath string, content []byte) (*BasicResolver, error) {
+	resolver := NewBasicResolver()
+	resolver.documentPath = absPath
+	resolver.namespace = path
+	resolver.referenceLoader = f
+
+	if err := f.decoder.Decode(&namedReader{Reader: bytes.NewReader(content), name: path}, resolver); err != nil {
+		return nil, err
+	}
+
+	return resolver, nil
+}
+
+// relativeToWorkingDir returns the path relative to the working directory when possible, which is shorter in errors
+func relativeToWorkingDir(absPath string) string {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return absPath
+	}
+
+	relPath, err := filepath.Rel(workingDir, absPath)
+	if err != nil {
+		return absPath
+	}
+
+	return relPath
+}
+
+// namedReader identifies the file of the content, like the files of the file system, allowing the decoders to detect its format
+type namedReader struct {
+	io.Reader
+	name string
+}
+
+func (n *namedReader) Name() string {
+	return n.name
+}
+
+// NewFileReferenceLoader creates a loader that decodes the external documents of the file system with the "decoder"
+func NewFileReferenceLoader(decoder parser.Decoder, searchPaths ...string) *FileReferenceLoader {
+	return &FileReferenceLoader{
+		decoder:     decoder,
+		searchPaths: searchPaths,
+		readFile:    os.ReadFile,
+		documents:   make(map[string]loadedDocument),
+	}
+}
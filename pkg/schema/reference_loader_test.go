@@ -0,0 +1,272 @@
+package schema_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/multierror"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/parser/yaml"
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
+)
+
+func TestShouldResolveExternalReferences(t *testing.T) {
+	dir := t.TempDir()
+
+	writeDocument(t, filepath.Join(dir, "shared", "common.yaml"), `version: "1.0"
+name: shared
+types:
+  Money:
+    type: object
+    description: Valor monetário
+    properties:
+      amount:
+        type: integer
+        value: 100
+      currency:
+        $ref: 'currency.yaml#/types/Currency'
+`)
+
+	// Found in the search path, since it doesn't exist in the directory of common.yaml
+	writeDocument(t, filepath.Join(dir, "lib", "currency.yaml"), `version: "1.0"
+name: currency
+types:
+  Currency:
+    type: string
+    value: BRL
+`)
+
+	resolver := decodeDocument(t, filepath.Join(dir, "service", "lifecycle.yaml"), `version: "1.0"
+name: service
+types:
+  Price:
+    $ref: '../shared/common.yaml#/types/Money'
+`, filepath.Join(dir, "lib"))
+
+	typeDefinitions, err := resolver.GetTypes()
+	assertNoError(t, err)
+	assertLength(t, 1, typeDefinitions)
+
+	price := typeDefintionToRealType[*types.ObjectReference](t, typeDefinitions[0])
+	assertTypePath(t, "#/types/Price", price)
+	assertTypeDescription(t, "Valor monetário", price)
+
+	properties := typeDescriberSliceToMap(price.Properties())
+	assertTypeExistInMap(t, "#/types/Money/properties/currency", properties)
+	assertScalarValue(t, "BRL", properties["#/types/Money/properties/currency"])
+}
+
+func TestShouldIdentifyRecursiveReferenceAcrossFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	writeDocument(t, filepath.Join(dir, "other.yaml"), `version: "1.0"
+name: other
+types:
+  Other:
+    $ref: 'lifecycle.yaml#/types/Main'
+`)
+
+	resolver := decodeDocument(t, filepath.Join(dir, "lifecycle.yaml"), `version: "1.0"
+name: main
+types:
+  Main:
+    $ref: 'other.yaml#/types/Other'
+`)
+
+	_, err := resolver.GetTypes()
+	if err == nil {
+		t.Fatal("expected error, received nil")
+	}
+
+	if !strings.Contains(err.Error(), "recursive reference") {
+		t.Errorf("expected recursive reference error, received '%s'", err)
+	}
+}
+
+func TestShouldNameOriginatingFileOfExternalErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	writeDocument(t, filepath.Join(dir, "common.yaml"), `version: "1.0"
+name: shared
+types:
+  Money:
+    $ref: '#/types/Mony'
+`)
+
+	resolver := decodeDocument(t, filepath.Join(dir, "lifecycle.yaml"), `version: "1.0"
+name: main
+types:
+  Price:
+    $ref: 'common.yaml#/types/Money'
+  Tax:
+    $ref: 'common.yaml#/types/Tex'
+`)
+
+	_, err := resolver.GetTypes()
+	if err == nil {
+		t.Fatal("expected error, received nil")
+	}
+
+	for _, expected := range []string{
+		"definition '#/types/Mony' referenced in 'common.yaml#/types/Money' not found in declared definitions, did you mean '#/types/Money'?",
+		"definition 'common.yaml#/types/Tex' referenced in '#/types/Tax' not found in declared definitions",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected '%s' in error, received '%s'", expected, err)
+		}
+	}
+}
+
+func TestShouldReportPositionOfUnresolvedReferences(t *testing.T) {
+	dir := t.TempDir()
+
+	writeDocument(t, filepath.Join(dir, "common.yaml"), `version: "1.0"
+name: shared
+types:
+  Money:
+    $ref: '#/types/Mony'
+`)
+
+	resolver := decodeDocument(t, filepath.Join(dir, "lifecycle.yaml"), `version: "1.0"
+name: main
+types:
+  Price:
+    $ref: 'common.yaml#/types/Money'
+  Tax:
+    type: object
+    properties:
+      rate:
+        $ref: '#/types/Rate'
+`)
+
+	_, err := resolver.GetTypes()
+
+	errs := multierror.Flatten(err)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, received %d: %v", len(errs), err)
+	}
+
+	expected := []schema.DefinitionError{
+		{File: "common.yaml", Line: 5, Column: 11, Path: "#/types/Money"},
+		{Line: 10, Column: 15, Path: "#/types/Tax/properties/rate"},
+	}
+
+	for i := range expected {
+		var definitionErr *schema.DefinitionError
+		if !errors.As(errs[i], &definitionErr) {
+			t.Fatalf("expected definition error, received '%v'", errs[i])
+		}
+
+		if definitionErr.File != expected[i].File || definitionErr.Path != expected[i].Path {
+			t.Errorf(
+				"expected '%s%s' definition, received '%s%s'",
+				expected[i].File,
+				expected[i].Path,
+				definitionErr.File,
+				definitionErr.Path,
+			)
+		}
+
+		if definitionErr.Line != expected[i].Line || definitionErr.Column != expected[i].Column {
+			t.Errorf(
+				"expected position %d:%d, received %d:%d",
+				expected[i].Line,
+				expected[i].Column,
+				definitionErr.Line,
+				definitionErr.Column,
+			)
+		}
+	}
+}
+
+func TestShouldLoadExternalReferencesWithFileReader(t *testing.T) {
+	dir := t.TempDir()
+
+	// The file on disk differs from the file read by the file reader, like the files of a git revision
+	writeDocument(t, filepath.Join(dir, "common.yaml"), `version: "1.0"
+name: shared
+types:
+  Currency:
+    type: string
+    value: USD
+`)
+
+	lifecycleFile := filepath.Join(dir, "lifecycle.yaml")
+	writeDocument(t, lifecycleFile, `version: "1.0"
+name: main
+types:
+  Price:
+    $ref: 'common.yaml#/types/Currency'
+`)
+
+	file, err := os.Open(lifecycleFile)
+	assertNoError(t, err)
+	defer file.Close()
+
+	var readPaths []string
+
+	referenceLoader := schema.NewFileReferenceLoader(yaml.NewDecoder())
+	referenceLoader.SetFileReader(func(path string) ([]byte, error) {
+		readPaths = append(readPaths, path)
+
+		return []byte(`version: "1.0"
+name: shared
+types:
+  Currency:
+    type: string
+    value: BRL
+`), nil
+	})
+
+	resolver := schema.NewBasicResolver()
+	assertNoError(t, resolver.SetDocumentPath(lifecycleFile))
+	resolver.SetReferenceLoader(referenceLoader)
+	assertNoError(t, yaml.NewDecoder().Decode(file, resolver))
+
+	typeDefinitions, err := resolver.GetTypes()
+	assertNoError(t, err)
+	assertLength(t, 1, typeDefinitions)
+	assertScalarValue(t, "BRL", typeDefinitions[0])
+
+	if len(readPaths) != 1 || filepath.Base(readPaths[0]) != "common.yaml" {
+		t.Errorf("expected 'common.yaml' to be read, received '%v'", readPaths)
+	}
+}
+
+func writeDocument(t *testing.T, path, content string) {
+	t.Helper()
+
+	assertNoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assertNoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func decodeDocument(t *testing.T, path, content string, searchPaths ...string) *schema.BasicResolver {
+	t.Helper()
+
+	writeDocument(t, path, content)
+
+	// The errors are easier to compare using paths relative to the working directory
+	workingDir, err := os.Getwd()
+	assertNoError(t, err)
+	assertNoError(t, os.Chdir(filepath.Dir(path)))
+	t.Cleanup(func() {
+		os.Chdir(workingDir)
+	})
+
+	file, err := os.Open(path)
+	assertNoError(t, err)
+	defer file.Close()
+
+	decoder := yaml.NewDecoder()
+
+	resolver := schema.NewBasicResolver()
+	assertNoError(t, resolver.SetDocumentPath(path))
+	resolver.SetReferenceLoader(schema.NewFileReferenceLoader(decoder, searchPaths...))
+
+	assertNoError(t, decoder.Decode(file, resolver))
+
+	return resolver
+}
@@ -3,6 +3,7 @@ package schema
 import "github.com/madeiramadeirabr/action-lifecycledoc/pkg/schema/types"
 
 type Resolver interface {
+	GetProject() (*types.Project, error)
 	GetConfluence() (*types.Confluence, error)
 	GetPublishedEvents() ([]*types.PublishedEvent, error)
 	GetConsumedEvents() ([]*types.ConsumedEvent, error)
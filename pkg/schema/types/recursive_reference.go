@@ -0,0 +1,50 @@
+package types
+
+// RecursiveReference is a reference to a definition that contains the reference itself, like the children of a category.
+// The referenced definition is not expanded to avoid infinite structures
+type RecursiveReference struct {
+	reference *Reference
+
+	target TypeDescriber
+}
+
+// Reference returns the reference path, like #/types/Category, identifying the referenced definition across files
+func (r *RecursiveReference) Reference() string {
+	return r.reference.Reference()
+}
+
+// Target returns the referenced definition, which contains this reference
+func (r *RecursiveReference) Target() TypeDescriber {
+	return r.target
+}
+
+func (r *RecursiveReference) Name() string {
+	return r.reference.Name()
+}
+
+func (r *RecursiveReference) Path() string {
+	return r.reference.Path()
+}
+
+func (r *RecursiveReference) Type() string {
+	return r.target.Type()
+}
+
+func (r *RecursiveReference) Description() string {
+	if len(r.reference.Description()) > 0 {
+		return r.reference.Description()
+	}
+
+	return r.target.Description()
+}
+
+func (r *RecursiveReference) Nullable() bool {
+	return r.reference.Nullable()
+}
+
+func NewRecursiveReference(reference *Reference, target TypeDescriber) *RecursiveReference {
+	return &RecursiveReference{
+		reference: reference,
+		target:    target,
+	}
+}
@@ -0,0 +1,254 @@
+// textdiff package provides a line based diff in the unified format
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	contextLines = 3
+)
+
+type operation uint8
+
+const (
+	operationEqual operation = iota
+	operationDelete
+	operationInsert
+)
+
+type edit struct {
+	operation operation
+	line      string
+}
+
+/*
+Unified returns the differences between "oldLines" and "newLines" in the unified format.
+
+Returns an empty string when both slices are identical.
+*/
+func Unified(oldName, newName string, oldLines, newLines []string) string {
+	edits := diff(oldLines, newLines)
+
+	hunks := groupHunks(edits)
+	if len(hunks) < 1 {
+		return ""
+	}
+
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "--- %s\n+++ %s\n", oldName, newName)
+
+	for i := range hunks {
+		hunks[i].write(out, edits)
+	}
+
+	return out.String()
+}
+
+type hunk struct {
+	// start and end are indexes of the edits covered by the hunk
+	start, end int
+
+	oldStart, oldCount int
+	newStart, newCount int
+}
+
+func (h *hunk) write(out *strings.Builder, edits []edit) {
+	fmt.Fprintf(
+		out,
+		"@@ -%s +%s @@\n",
+		formatRange(h.oldStart, h.oldCount),
+		formatRange(h.newStart, h.newCount),
+	)
+
+	for i := h.start; i < h.end; i++ {
+		switch edits[i].operation {
+		case operationEqual:
+			out.WriteRune(' ')
+		case operationDelete:
+			out.WriteRune('-')
+		case operationInsert:
+			out.WriteRune('+')
+		}
+
+		out.WriteString(edits[i].line)
+		out.WriteRune('\n')
+	}
+}
+
+func formatRange(start, count int) string {
+	// Empty ranges point to the line before the change, as GNU diff does
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func groupHunks(edits []edit) []*hunk {
+	var (
+		hunks   []*hunk
+		current *hunk
+
+		// 1-based line numbers of the current edit
+		oldLine, newLine = 1, 1
+		// lastChange stores the index of the last edit that is not an equal operation
+		lastChange = -1
+	)
+
+	for i := range edits {
+		if edits[i].operation != operationEqual {
+			if current == nil || i-lastChange-1 > 2*contextLines {
+				if current != nil {
+					current.closeAt(edits, lastChange+1)
+				}
+
+				current = openHunk(edits, i, oldLine, newLine)
+				hunks = append(hunks, current)
+			}
+
+			lastChange = i
+		}
+
+		switch edits[i].operation {
+		case operationEqual:
+			oldLine++
+			newLine++
+		case operationDelete:
+			oldLine++
+		case operationInsert:
+			newLine++
+		}
+	}
+
+	if current != nil {
+		current.closeAt(edits, lastChange+1)
+	}
+
+	return hunks
+}
+
+func openHunk(edits []edit, changeIndex, oldLine, newLine int) *hunk {
+	start := changeIndex - contextLines
+	if start < 0 {
+		start = 0
+	}
+
+	// The leading context lines are always equal operations
+	leading := changeIndex - start
+
+	return &hunk{
+		start:    start,
+		oldStart: oldLine - leading,
+		newStart: newLine - leading,
+	}
+}
+
+func (h *hunk) closeAt(edits []edit, endOfChanges int) {
+	h.end = endOfChanges + contextLines
+	if h.end > len(edits) {
+		h.end = len(edits)
+	}
+
+	for i := h.start; i < h.end; i++ {
+		switch edits[i].operation {
+		case operationEqual:
+			h.oldCount++
+			h.newCount++
+		case operationDelete:
+			h.oldCount++
+		case operationInsert:
+			h.newCount++
+		}
+	}
+}
+
+// diff implements the Myers' diff algorithm
+func diff(a, b []string) []edit {
+	var (
+		n, m   = len(a), len(b)
+		max    = n + m
+		offset = max + 1
+
+		v     = make([]int, 2*max+3)
+		trace [][]int
+	)
+
+	for d := 0; d <= max; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(trace, a, b, offset)
+			}
+		}
+	}
+
+	return nil
+}
+
+func backtrack(trace [][]int, a, b []string, offset int) []edit {
+	var (
+		x, y  = len(a), len(b)
+		edits []edit
+	)
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var previousK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			previousK = k + 1
+		} else {
+			previousK = k - 1
+		}
+
+		previousX := v[offset+previousK]
+		previousY := previousX - previousK
+
+		for x > previousX && y > previousY {
+			edits = append(edits, edit{operation: operationEqual, line: a[x-1]})
+			x--
+			y--
+		}
+
+		if d > 0 {
+			if x == previousX {
+				edits = append(edits, edit{operation: operationInsert, line: b[y-1]})
+			} else {
+				edits = append(edits, edit{operation: operationDelete, line: a[x-1]})
+			}
+		}
+
+		x, y = previousX, previousY
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+
+	return edits
+}
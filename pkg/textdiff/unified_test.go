@@ -0,0 +1,104 @@
+package textdiff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/madeiramadeirabr/action-lifecycledoc/pkg/textdiff"
+)
+
+func TestShouldReturnEmptyDiffForIdenticalLines(t *testing.T) {
+	lines := []string{"a", "b", "c"}
+
+	if result := textdiff.Unified("old", "new", lines, lines); result != "" {
+		t.Errorf("expected empty diff, received '%s'", result)
+	}
+}
+
+func TestShouldWriteUnifiedDiff(t *testing.T) {
+	testCases := []struct {
+		name     string
+		old      string
+		new      string
+		expected string
+	}{
+		{
+			name: "should diff changed line",
+			old:  "a\nb\nc",
+			new:  "a\nB\nc",
+			expected: `--- old
++++ new
+@@ -1,3 +1,3 @@
+ a
+-b
++B
+ c
+`,
+		},
+		{
+			name: "should diff from empty",
+			old:  "",
+			new:  "a\nb",
+			expected: `--- old
++++ new
+@@ -0,0 +1,2 @@
++a
++b
+`,
+		},
+		{
+			name: "should split distant changes in hunks",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12",
+			new:  "0\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n13",
+			expected: `--- old
++++ new
+@@ -1,4 +1,4 @@
+-1
++0
+ 2
+ 3
+ 4
+@@ -9,4 +9,4 @@
+ 9
+ 10
+ 11
+-12
++13
+`,
+		},
+		{
+			name: "should merge close changes in one hunk",
+			old:  "1\n2\n3\n4\n5\n6",
+			new:  "1\n3\n4\n5\n6\n7",
+			expected: `--- old
++++ new
+@@ -1,6 +1,6 @@
+ 1
+-2
+ 3
+ 4
+ 5
+ 6
++7
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := textdiff.Unified("old", "new", splitLines(testCase.old), splitLines(testCase.new))
+
+			if result != testCase.expected {
+				t.Errorf("expected '%s', received '%s'", testCase.expected, result)
+			}
+		})
+	}
+}
+
+func splitLines(s string) []string {
+	if len(s) < 1 {
+		return nil
+	}
+
+	return strings.Split(s, "\n")
+}